@@ -0,0 +1,312 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// A Client calls the /v1 API of a single simulated service.
+type Client struct {
+	base *url.URL
+	// HTTPClient sends every request. It defaults to http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// NewClient is the constructor for Client.
+// The address is the base URL of the service, e.g. http://10.0.0.1:8080.
+func NewClient(address string) (*Client, error) {
+	var base, err = url.Parse(address)
+	if err != nil {
+		return nil, err
+	}
+	if base.Scheme == "" || base.Host == "" {
+		return nil, fmt.Errorf("expected an absolute URL, got %q", address)
+	}
+	base.Path = strings.TrimSuffix(base.Path, "/")
+	return &Client{base: base, HTTPClient: http.DefaultClient}, nil
+}
+
+// Address returns the base URL of the service.
+func (client *Client) Address() string {
+	return client.base.String()
+}
+
+// Health reports whether the service is alive under the load it holds.
+// A dead service is not an error; check Health.Alive.
+func (client *Client) Health(ctx context.Context) (Health, error) {
+	var health Health
+	var err = client.do(ctx, http.MethodGet, "/health", nil, nil, &health, http.StatusServiceUnavailable)
+	return health, err
+}
+
+// HealthAt reports whether the service would be alive under the given load.
+func (client *Client) HealthAt(ctx context.Context, load uint64) (Health, error) {
+	var health Health
+	var err = client.do(ctx, http.MethodGet, "/health", loadQuery(load), nil, &health, http.StatusServiceUnavailable)
+	return health, err
+}
+
+// Ready reports whether the service should receive more traffic under the load it holds.
+// A service which isn't ready is not an error; check Readiness.Ready.
+func (client *Client) Ready(ctx context.Context) (Readiness, error) {
+	var readiness Readiness
+	var err = client.do(ctx, http.MethodGet, "/ready", nil, nil, &readiness, http.StatusServiceUnavailable)
+	return readiness, err
+}
+
+// Load returns the load held by the service.
+func (client *Client) Load(ctx context.Context) (Load, error) {
+	var load Load
+	var err = client.do(ctx, http.MethodGet, "/load", nil, nil, &load)
+	return load, err
+}
+
+// SetLoad sets the load held by the service, overriding the measured load.
+func (client *Client) SetLoad(ctx context.Context, load uint64) (Load, error) {
+	var response Load
+	var err = client.do(ctx, http.MethodPost, "/load", nil, SetLoadRequest{Load: &load}, &response)
+	return response, err
+}
+
+// ClearLoad goes back to measuring the load from the requests the service receives.
+func (client *Client) ClearLoad(ctx context.Context) (Load, error) {
+	var response Load
+	var err = client.do(ctx, http.MethodDelete, "/load", nil, nil, &response)
+	return response, err
+}
+
+// Throughput returns the throughput of the service under the load it holds.
+func (client *Client) Throughput(ctx context.Context) (Throughput, error) {
+	var throughput Throughput
+	var err = client.do(ctx, http.MethodGet, "/throughput", nil, nil, &throughput)
+	return throughput, err
+}
+
+// ThroughputAt returns the throughput of the service under the given load.
+func (client *Client) ThroughputAt(ctx context.Context, load uint64) (Throughput, error) {
+	var throughput Throughput
+	var err = client.do(ctx, http.MethodGet, "/throughput", loadQuery(load), nil, &throughput)
+	return throughput, err
+}
+
+// Explain returns how the service arrived at its throughput under the load it holds.
+func (client *Client) Explain(ctx context.Context) (Explanation, error) {
+	var explanation Explanation
+	var err = client.do(ctx, http.MethodGet, "/throughput/explain", nil, nil, &explanation)
+	return explanation, err
+}
+
+// ExplainAt returns how the service arrives at its throughput under the given load.
+func (client *Client) ExplainAt(ctx context.Context, load uint64) (Explanation, error) {
+	var explanation Explanation
+	var err = client.do(ctx, http.MethodGet, "/throughput/explain", loadQuery(load), nil, &explanation)
+	return explanation, err
+}
+
+// Work burns one request's worth of CPU on a service in real mode.
+func (client *Client) Work(ctx context.Context) (WorkResult, error) {
+	var result WorkResult
+	var err = client.do(ctx, http.MethodPost, "/work", nil, nil, &result)
+	return result, err
+}
+
+// Latency returns the latency percentiles of the service under the load it holds.
+func (client *Client) Latency(ctx context.Context) (Latency, error) {
+	var latency Latency
+	var err = client.do(ctx, http.MethodGet, "/latency", nil, nil, &latency)
+	return latency, err
+}
+
+// LatencyAt returns the latency percentiles of the service under the given load.
+func (client *Client) LatencyAt(ctx context.Context, load uint64) (Latency, error) {
+	var latency Latency
+	var err = client.do(ctx, http.MethodGet, "/latency", loadQuery(load), nil, &latency)
+	return latency, err
+}
+
+// Limits returns the capacity limits of the service.
+func (client *Client) Limits(ctx context.Context) (Limits, error) {
+	var limits Limits
+	var err = client.do(ctx, http.MethodGet, "/limits", nil, nil, &limits)
+	return limits, err
+}
+
+// UpdateLimits changes the capacity limits of the service, and returns the new limits.
+func (client *Client) UpdateLimits(ctx context.Context, update LimitsUpdate) (Limits, error) {
+	var limits Limits
+	var err = client.do(ctx, http.MethodPost, "/limits", nil, update, &limits)
+	return limits, err
+}
+
+// Neighbors lists every noisy neighbor of the service.
+func (client *Client) Neighbors(ctx context.Context) (NeighborList, error) {
+	var list NeighborList
+	var err = client.do(ctx, http.MethodGet, "/neighbors", nil, nil, &list)
+	return list, err
+}
+
+// Neighbor describes the noisy neighbor with the given ID.
+func (client *Client) Neighbor(ctx context.Context, id string) (Neighbor, error) {
+	var neighbor Neighbor
+	var err = client.do(ctx, http.MethodGet, "/neighbors/"+url.PathEscape(id), nil, nil, &neighbor)
+	return neighbor, err
+}
+
+// AddNeighbor steals resources from the service, returning the lease which holds them.
+// If the service won't admit the neighbor, the error is an *Error with the
+// code CodeAdmissionRejected.
+func (client *Client) AddNeighbor(ctx context.Context, request NeighborRequest) (NeighborAdded, error) {
+	var added NeighborAdded
+	var err = client.do(ctx, http.MethodPost, "/neighbors", nil, request, &added)
+	return added, err
+}
+
+// RenewNeighbor extends the lease of the noisy neighbor with the given ID.
+// A TTL of 0 uses the service's default.
+func (client *Client) RenewNeighbor(ctx context.Context, id string, ttl time.Duration) (Neighbor, error) {
+	var neighbor Neighbor
+	var request = RenewRequest{TTLSeconds: uint64(ttl / time.Second)}
+	var err = client.do(ctx, http.MethodPost, "/neighbors/"+url.PathEscape(id)+"/renew", nil, request, &neighbor)
+	return neighbor, err
+}
+
+// RemoveNeighbor releases the lease of the noisy neighbor with the given ID,
+// restoring the resources it stole.
+func (client *Client) RemoveNeighbor(ctx context.Context, id string) (NeighborRemoved, error) {
+	var removed NeighborRemoved
+	var err = client.do(ctx, http.MethodDelete, "/neighbors/"+url.PathEscape(id), nil, nil, &removed)
+	return removed, err
+}
+
+// Events returns a page of the events recorded in the service's history, oldest first.
+func (client *Client) Events(ctx context.Context, query EventQuery) (EventPage, error) {
+	var values = url.Values{}
+	if len(query.Types) > 0 {
+		values.Set("type", strings.Join(query.Types, ","))
+	}
+	if !query.Since.IsZero() {
+		values.Set("since", query.Since.Format(time.RFC3339Nano))
+	}
+	if !query.Until.IsZero() {
+		values.Set("until", query.Until.Format(time.RFC3339Nano))
+	}
+	if query.After > 0 {
+		values.Set("after", strconv.FormatUint(query.After, 10))
+	}
+	if query.Limit > 0 {
+		values.Set("limit", strconv.Itoa(query.Limit))
+	}
+	var page EventPage
+	var err = client.do(ctx, http.MethodGet, "/events", values, nil, &page)
+	return page, err
+}
+
+// History returns the samples the service took of itself between the times,
+// averaged over the resolution. Zero times leave that end of the range open.
+// The service keeps a limited history at each resolution.
+func (client *Client) History(ctx context.Context, resolution time.Duration, since, until time.Time) (History, error) {
+	var values = url.Values{"resolution": {resolution.String()}}
+	if !since.IsZero() {
+		values.Set("since", since.Format(time.RFC3339Nano))
+	}
+	if !until.IsZero() {
+		values.Set("until", until.Format(time.RFC3339Nano))
+	}
+	var history History
+	var err = client.do(ctx, http.MethodGet, "/history", values, nil, &history)
+	return history, err
+}
+
+// IsAdmissionRejected returns true if the error is a service refusing to admit a noisy neighbor.
+func IsAdmissionRejected(err error) bool {
+	var apiErr, ok = err.(*Error)
+	return ok && apiErr.Code == CodeAdmissionRejected
+}
+
+// IsNotFound returns true if the error is a service not knowing the requested resource.
+func IsNotFound(err error) bool {
+	var apiErr, ok = err.(*Error)
+	return ok && apiErr.Code == CodeNotFound
+}
+
+func loadQuery(load uint64) url.Values {
+	return url.Values{"load": {strconv.FormatUint(load, 10)}}
+}
+
+// do sends a request to the path under /v1, encoding the body as JSON if it's
+// not nil, and decodes the response into out. Responses with a 2xx status, or
+// one of the accepted statuses, are decoded into out; any other response is
+// decoded into an *Error.
+func (client *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}, accepted ...int) error {
+	var uri = *client.base
+	uri.Path = client.base.Path + Version + path
+	uri.RawQuery = query.Encode()
+
+	var reader io.Reader
+	if body != nil {
+		var encoded, err = json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	var req, err = http.NewRequest(method, uri.String(), reader)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	contents, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if isAccepted(resp.StatusCode, accepted) {
+		return json.Unmarshal(contents, out)
+	}
+	return decodeError(resp.StatusCode, contents)
+}
+
+// decodeError decodes the body of an unsuccessful response into an *Error.
+// A body which isn't in the error envelope becomes the error's message.
+func decodeError(status int, contents []byte) error {
+	var errorResponse ErrorResponse
+	if err := json.Unmarshal(contents, &errorResponse); err != nil || errorResponse.Error == nil {
+		return &Error{
+			Status:  status,
+			Code:    CodeInternal,
+			Message: strings.TrimSpace(string(contents)),
+		}
+	}
+	errorResponse.Error.Status = status
+	return errorResponse.Error
+}
+
+func isAccepted(status int, accepted []int) bool {
+	if status >= 200 && status < 300 {
+		return true
+	}
+	for _, s := range accepted {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// A StreamEvent is one event from GET /v1/stream. Exactly one of its fields is set.
+type StreamEvent struct {
+	State      *State
+	Throughput *ThroughputSample
+}
+
+// Stream subscribes to the state changes of the service, and to a throughput
+// sample every interval. An interval of 0 uses the service's default.
+// Each event is passed to handle until the context is done, or the service
+// ends the stream. Services end their streams before their write timeout
+// cuts them off, so call Stream again to stay subscribed.
+func (client *Client) Stream(ctx context.Context, interval time.Duration, handle func(StreamEvent)) error {
+	var uri = *client.base
+	uri.Path = client.base.Path + Version + "/stream"
+	if interval > 0 {
+		uri.RawQuery = url.Values{"interval": {interval.String()}}.Encode()
+	}
+	var req, err = http.NewRequest(http.MethodGet, uri.String(), nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := client.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var contents, err = ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		return decodeError(resp.StatusCode, contents)
+	}
+
+	// Each event is a block of "field: value" lines, ended by a blank line.
+	var scanner = bufio.NewScanner(resp.Body)
+	var name, data string
+	for scanner.Scan() {
+		var line = scanner.Text()
+		switch {
+		case line == "":
+			if event, ok := decodeStreamEvent(name, data); ok {
+				handle(event)
+			}
+			name, data = "", ""
+		case strings.HasPrefix(line, "event:"):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return scanner.Err()
+}
+
+// decodeStreamEvent decodes the data of the named event.
+// Events which aren't known, or can't be decoded, are skipped.
+func decodeStreamEvent(name, data string) (StreamEvent, bool) {
+	var event StreamEvent
+	var err error
+	switch name {
+	case StreamState:
+		event.State = new(State)
+		err = json.Unmarshal([]byte(data), event.State)
+	case StreamThroughput:
+		event.Throughput = new(ThroughputSample)
+		err = json.Unmarshal([]byte(data), event.Throughput)
+	default:
+		return event, false
+	}
+	return event, err == nil
+}
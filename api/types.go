@@ -0,0 +1,361 @@
+// Package api holds the types of the simulated service's versioned HTTP API,
+// served under /v1, and a Client for calling it.
+package api
+
+import (
+	"fmt"
+	"time"
+)
+
+// Version is the path prefix of every route in this API.
+const Version = "/v1"
+
+// Error codes carried by an Error.
+const (
+	CodeInvalidArgument   = "invalid_argument"
+	CodeNotFound          = "not_found"
+	CodeMethodNotAllowed  = "method_not_allowed"
+	CodeAdmissionRejected = "admission_rejected"
+	CodeUnavailable       = "unavailable"
+	CodeInternal          = "internal"
+)
+
+// ErrorResponse is the body of every unsuccessful response.
+type ErrorResponse struct {
+	Error *Error `json:"error"`
+}
+
+// Error describes why a request failed.
+// Status is the HTTP status code of the response, and isn't part of the body.
+type Error struct {
+	Status  int    `json:"-"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Admission is set when the code is CodeAdmissionRejected.
+	Admission *AdmissionRejection `json:"admission,omitempty"`
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("%s (%d %s)", err.Message, err.Status, err.Code)
+}
+
+// AdmissionRejection describes the resource which a rejected neighbor
+// would have pushed past the ceiling of its QoS class.
+type AdmissionRejection struct {
+	QoS       string `json:"qos"`
+	Resource  string `json:"resource"`
+	Requested uint64 `json:"requested"`
+	Stolen    uint64 `json:"stolen"`
+	Ceiling   uint64 `json:"ceiling"`
+}
+
+// Resources holds a percentage, from 0 to 100, of each resource
+// which noisy neighbors can steal.
+type Resources struct {
+	CPU             uint64 `json:"cpu"`
+	MemoryBandwidth uint64 `json:"memory_bandwidth"`
+	DiskIO          uint64 `json:"disk_io"`
+	Network         uint64 `json:"network"`
+}
+
+// Health is returned by GET /v1/health.
+// The status is 503 when the service is dead.
+type Health struct {
+	Alive bool   `json:"alive"`
+	Load  uint64 `json:"load"`
+	// AvailableCPU is the percentage of CPU left to the service.
+	AvailableCPU float64   `json:"available_cpu"`
+	Stolen       Resources `json:"stolen"`
+}
+
+// Readiness is returned by GET /v1/ready.
+// The status is 503 when the service shouldn't receive more traffic.
+type Readiness struct {
+	Ready     bool   `json:"ready"`
+	Load      uint64 `json:"load"`
+	SoftLimit uint64 `json:"soft_limit"`
+}
+
+// Load is returned by /v1/load. Source is "set" when the load was set
+// by a caller, or "measured" when it's counted from the requests received.
+type Load struct {
+	Load   uint64 `json:"load"`
+	Source string `json:"source"`
+}
+
+// SetLoadRequest is the body of POST /v1/load. The load is required.
+type SetLoadRequest struct {
+	Load *uint64 `json:"load"`
+}
+
+// Throughput is returned by GET /v1/throughput.
+type Throughput struct {
+	Load       uint64 `json:"load"`
+	Throughput uint64 `json:"throughput"`
+}
+
+// Latency is returned by GET /v1/latency, in milliseconds.
+// A dead service reports the request timeout for every percentile.
+type Latency struct {
+	Load  uint64  `json:"load"`
+	Alive bool    `json:"alive"`
+	P50   float64 `json:"p50_ms"`
+	P90   float64 `json:"p90_ms"`
+	P99   float64 `json:"p99_ms"`
+	P999  float64 `json:"p999_ms"`
+}
+
+// Limits is returned by /v1/limits. The modified limits and available
+// throughput account for the resources stolen by noisy neighbors.
+type Limits struct {
+	MaxThroughput       uint64 `json:"max_throughput"`
+	SoftLimit           uint64 `json:"soft_limit"`
+	HardLimit           uint64 `json:"hard_limit"`
+	AvailableThroughput uint64 `json:"available_throughput"`
+	ModifiedSoftLimit   uint64 `json:"modified_soft_limit"`
+	ModifiedHardLimit   uint64 `json:"modified_hard_limit"`
+}
+
+// LimitsUpdate is the body of POST /v1/limits.
+// Limits which are left out keep their current value.
+type LimitsUpdate struct {
+	MaxThroughput *uint64 `json:"max_throughput,omitempty"`
+	SoftLimit     *uint64 `json:"soft_limit,omitempty"`
+	HardLimit     *uint64 `json:"hard_limit,omitempty"`
+}
+
+// Neighbor describes a noisy neighbor, and the lease on the resources it stole.
+type Neighbor struct {
+	ID      string `json:"id"`
+	Address string `json:"address"`
+	Resources
+	StartedAt time.Time `json:"started_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Label     string    `json:"label,omitempty"`
+	JobID     string    `json:"job_id,omitempty"`
+	QoS       string    `json:"qos"`
+}
+
+// NeighborRequest is the body of POST /v1/neighbors.
+// At least one resource must be stolen. A TTL of 0 uses the server's default.
+type NeighborRequest struct {
+	Resources
+	TTLSeconds uint64 `json:"ttl_seconds,omitempty"`
+	Label      string `json:"label,omitempty"`
+	JobID      string `json:"job_id,omitempty"`
+	// QoS is guaranteed, burstable or best-effort. Empty means guaranteed.
+	QoS string `json:"qos,omitempty"`
+}
+
+// RenewRequest is the body of POST /v1/neighbors/{id}/renew.
+// A TTL of 0 uses the server's default.
+type RenewRequest struct {
+	TTLSeconds uint64 `json:"ttl_seconds,omitempty"`
+}
+
+// NeighborAdded is returned with a 201 by POST /v1/neighbors.
+type NeighborAdded struct {
+	Neighbor       Neighbor  `json:"neighbor"`
+	PreviousStolen Resources `json:"previous_stolen"`
+	Stolen         Resources `json:"stolen"`
+}
+
+// NeighborRemoved is returned by DELETE /v1/neighbors/{id}.
+type NeighborRemoved struct {
+	Neighbor Neighbor  `json:"neighbor"`
+	Stolen   Resources `json:"stolen"`
+}
+
+// NeighborList is returned by GET /v1/neighbors.
+type NeighborList struct {
+	Neighbors []Neighbor `json:"neighbors"`
+	Stolen    Resources  `json:"stolen"`
+}
+
+// The names of the events sent by GET /v1/stream.
+const (
+	StreamState      = "state"
+	StreamThroughput = "throughput"
+)
+
+// State is the data of a state event from GET /v1/stream. One is sent as
+// soon as the stream opens, and another whenever the limits, stolen resources,
+// load or liveness of the service change.
+type State struct {
+	Time time.Time `json:"time"`
+	Limits
+	Stolen    Resources `json:"stolen"`
+	Neighbors int       `json:"neighbors"`
+	Load      uint64    `json:"load"`
+	// LoadSource is "set" or "measured", like Load.Source.
+	LoadSource string `json:"load_source"`
+	Alive      bool   `json:"alive"`
+	Ready      bool   `json:"ready"`
+	Dying      bool   `json:"dying"`
+	Draining   bool   `json:"draining"`
+}
+
+// ThroughputSample is the data of a throughput event from GET /v1/stream,
+// sent once per interval.
+type ThroughputSample struct {
+	Time       time.Time `json:"time"`
+	Load       uint64    `json:"load"`
+	Throughput uint64    `json:"throughput"`
+	// AvailableCPU is the percentage of CPU left to the service.
+	AvailableCPU float64 `json:"available_cpu"`
+}
+
+// The types of the events recorded in a service's history.
+const (
+	EventNeighborAdded    = "neighbor_added"
+	EventNeighborRejected = "neighbor_rejected"
+	EventNeighborRemoved  = "neighbor_removed"
+	EventNeighborExpired  = "neighbor_expired"
+	EventLimitsChanged    = "limits_changed"
+	EventReconfigured     = "reconfigured"
+	EventLoadSet          = "load_set"
+	EventLoadCleared      = "load_cleared"
+	EventAlive            = "alive"
+	EventDead             = "dead"
+	EventDying            = "dying"
+	EventDraining         = "draining"
+)
+
+// EventTypes lists every type of event, in the order above.
+var EventTypes = []string{
+	EventNeighborAdded, EventNeighborRejected, EventNeighborRemoved, EventNeighborExpired,
+	EventLimitsChanged, EventReconfigured, EventLoadSet, EventLoadCleared,
+	EventAlive, EventDead, EventDying, EventDraining,
+}
+
+// An Event is a change in the state of a service, as recorded in its history.
+type Event struct {
+	// ID goes up by one with each event recorded, so it orders the events,
+	// and is the cursor for paging through them.
+	ID      uint64    `json:"id"`
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Message string    `json:"message"`
+	// Neighbor is set by neighbor_added, neighbor_removed and neighbor_expired.
+	Neighbor *Neighbor `json:"neighbor,omitempty"`
+	// Admission is set by neighbor_rejected.
+	Admission *AdmissionRejection `json:"admission,omitempty"`
+	// Limits is set to the new limits by limits_changed and reconfigured.
+	Limits *Limits `json:"limits,omitempty"`
+	// Load is set by load_set, alive and dead.
+	Load *uint64 `json:"load,omitempty"`
+}
+
+// EventPage is returned by GET /v1/events: the oldest events matching the query.
+type EventPage struct {
+	Events []Event `json:"events"`
+	// NextAfter is passed as the after param to fetch the next page.
+	// It's 0 once there are no more events.
+	NextAfter uint64 `json:"next_after"`
+}
+
+// EventQuery selects the events returned by GET /v1/events.
+// Fields left as their zero value don't filter the events.
+type EventQuery struct {
+	// Types selects the events with any of the types.
+	Types []string
+	// Since and Until select the events recorded within the times, inclusive.
+	Since, Until time.Time
+	// After selects the events with an ID greater than After.
+	After uint64
+	// Limit is the most events on a page. 0 uses the service's default.
+	Limit int
+}
+
+// History is returned by GET /v1/history: samples of the service, oldest first.
+type History struct {
+	// Resolution is the span covered by each point, e.g. "10s".
+	Resolution string         `json:"resolution"`
+	Points     []HistoryPoint `json:"points"`
+}
+
+// A HistoryPoint averages the samples taken of the service within the span
+// of one point, starting at Time. The last point may still be filling up.
+type HistoryPoint struct {
+	Time       time.Time `json:"time"`
+	Throughput float64   `json:"throughput"`
+	// AvailableCPU is the percentage of CPU left to the service.
+	AvailableCPU float64 `json:"available_cpu"`
+	Load         float64 `json:"load"`
+	// Samples is the number of samples averaged into the point.
+	Samples int `json:"samples"`
+}
+
+// Explanation is returned by GET /v1/throughput/explain. It holds the inputs
+// to the throughput under a load, the regime the load fell in, and the
+// intermediate values behind the result.
+type Explanation struct {
+	Load       uint64 `json:"load"`
+	Throughput uint64 `json:"throughput"`
+	// Model is the service's throughput model: step, mm1, mmc or usl,
+	// or real when the service measures its throughput instead.
+	Model string `json:"model"`
+	// Regime is where the load fell on the model's curve: healthy, saturated
+	// or degraded for step; idle, healthy or dropping for mm1 and mmc; and idle,
+	// scaling or retrograde for usl. Whatever the model, it's dead once the load
+	// passes the modified hard limit, or the service is dying.
+	// A real service's regime is always measured.
+	Regime string    `json:"regime"`
+	Stolen Resources `json:"stolen"`
+	// AvailableCPU is the percentage of CPU left to the service.
+	AvailableCPU float64 `json:"available_cpu"`
+	// Availability is the percentage of its capacity the service keeps
+	// once its sensitivity to each stolen resource is accounted for.
+	Availability float64 `json:"availability"`
+	// WarmUp is the percentage of its capacity the service has warmed up to.
+	WarmUp float64 `json:"warm_up"`
+	// MeasuredCPU is set when the available CPU counts the contention measured on the host.
+	MeasuredCPU *MeasuredCPU `json:"measured_cpu,omitempty"`
+	Limits      Limits       `json:"limits"`
+	Dying       bool         `json:"dying"`
+	// Values holds the model's intermediate values, by name.
+	Values map[string]float64 `json:"values"`
+	// Draw is set when the throughput was drawn at random, as it is in the
+	// degraded regime of the step model.
+	Draw *Draw `json:"draw,omitempty"`
+}
+
+// MeasuredCPU is the CPU contention measured on the host over the last second,
+// and how it's blended with the simulated neighbors into the available CPU.
+// Every value but Weight and QuotaCPUs is a percentage.
+type MeasuredCPU struct {
+	// Available is the CPU left once the cgroup's quota, as a share of the CPUs
+	// the service sees, and the measured contention are accounted for.
+	Available float64 `json:"available"`
+	// Simulated is the CPU left by the simulated noisy neighbors.
+	Simulated float64 `json:"simulated"`
+	// Weight is how much the measurement counts towards the available CPU, from 0 to 1.
+	Weight float64 `json:"weight"`
+	// Steal and IOWait are the shares of the host's CPU time stolen by the hypervisor,
+	// and spent waiting on I/O.
+	Steal  float64 `json:"steal"`
+	IOWait float64 `json:"iowait"`
+	// Throttled is the share of its cgroup's quota periods in which the service was throttled.
+	Throttled float64 `json:"throttled"`
+	// QuotaCPUs is the quota of the service's cgroup in cpu.max, in CPUs. 0 means unlimited.
+	QuotaCPUs float64 `json:"quota_cpus"`
+}
+
+// A Draw is a value drawn at random from Min up to, but not including, Max.
+type Draw struct {
+	Min   uint64 `json:"min"`
+	Max   uint64 `json:"max"`
+	Value uint64 `json:"value"`
+}
+
+// WorkResult is returned by /v1/work once a request's worth of CPU has been burned.
+// The times are in milliseconds.
+type WorkResult struct {
+	// CPU is the CPU time the work takes on an idle machine.
+	CPU float64 `json:"cpu_ms"`
+	// Queued is how long the request waited for a worker.
+	Queued float64 `json:"queued_ms"`
+	// Worked is how long the work took, which is longer than CPU
+	// when something else is competing for the CPU.
+	Worked float64 `json:"worked_ms"`
+}
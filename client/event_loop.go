@@ -1,11 +1,18 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
 )
 
+// requestTimeout bounds each call the event loop makes to a server.
+const requestTimeout = 2 * time.Second
+
 // What kind of events does the EventLoop handle?
 // 1. It handles changes of load.
 // 2. It handles requests to make a new machine.
@@ -15,6 +22,8 @@ type EventLoop struct {
 	eventStream  <-chan string
 	load         uint64
 	loadCallback func(uint64)
+	// servers are told about every change of load.
+	servers []*api.Client
 }
 
 func NewEventLoop() (*EventLoop, func(string)) {
@@ -38,6 +47,11 @@ func (loop *EventLoop) SetLoadCallback(f func(uint64)) {
 	loop.loadCallback = f
 }
 
+// SetServers replaces the servers which are told about every change of load.
+func (loop *EventLoop) SetServers(servers []*api.Client) {
+	loop.servers = servers
+}
+
 func (loop *EventLoop) watchEvents() {
 	for e := range loop.eventStream {
 		// Check what kind of event this is.
@@ -62,4 +76,12 @@ func (loop *EventLoop) watchEvents() {
 
 func (loop *EventLoop) sendLoad() {
 	loop.loadCallback(loop.load)
+	for _, server := range loop.servers {
+		var ctx, cancel = context.WithTimeout(context.Background(), requestTimeout)
+		var _, err = server.SetLoad(ctx, loop.load)
+		cancel()
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
 }
@@ -1,6 +1,9 @@
 package main
 
 import (
+	"fmt"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
 	ui "github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
 )
@@ -11,14 +14,19 @@ import (
 // And two batch jobs.
 type Machine struct {
 	*ui.Grid
+	name  string
 	chart *widgets.Sparkline
 	batch *widgets.Gauge
 	cpu   *widgets.PieChart
 }
 
+// maxSamples is the number of throughput samples kept on a machine's sparkline.
+const maxSamples = 60
+
 func NewMachine(name string, x1, y1, x2, y2 int) *Machine {
 	var machine = &Machine{
 		Grid:  ui.NewGrid(),
+		name:  name,
 		chart: widgets.NewSparkline(),
 		batch: widgets.NewGauge(),
 		cpu:   widgets.NewPieChart(),
@@ -66,3 +74,54 @@ func NewMachine(name string, x1, y1, x2, y2 int) *Machine {
 	)
 	return machine
 }
+
+// ObserveState shows the state of the machine's server.
+// The batch gauge shows the CPU stolen by noisy neighbors.
+func (machine *Machine) ObserveState(state api.State) {
+	var status = "alive"
+	if !state.Alive {
+		status = "dead"
+	}
+	machine.Grid.Title = fmt.Sprintf("%s (%s, load %d)", machine.name, status, state.Load)
+	machine.batch.Percent = int(minUint(state.Stolen.CPU, 100))
+	ui.Render(machine)
+}
+
+// Backfill replaces the machine's sparkline with the points, oldest first.
+func (machine *Machine) Backfill(points []api.HistoryPoint) {
+	if len(points) == 0 {
+		return
+	}
+	var data = make([]float64, 0, len(points))
+	for _, point := range points {
+		data = append(data, point.Throughput)
+	}
+	if len(data) > maxSamples {
+		data = data[len(data)-maxSamples:]
+	}
+	machine.chart.Data = data
+	ui.Render(machine)
+}
+
+// ObserveThroughput adds the sample to the machine's sparkline.
+func (machine *Machine) ObserveThroughput(sample api.ThroughputSample) {
+	var data = append(machine.chart.Data, float64(sample.Throughput))
+	if len(data) > maxSamples {
+		data = data[len(data)-maxSamples:]
+	}
+	machine.chart.Data = data
+	ui.Render(machine)
+}
+
+// ObserveError shows that the machine's server can't be reached.
+func (machine *Machine) ObserveError(err error) {
+	machine.Grid.Title = fmt.Sprintf("%s (%v)", machine.name, err)
+	ui.Render(machine)
+}
+
+func minUint(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -2,10 +2,18 @@ package main
 
 import (
 	"fmt"
+	"os"
+	"strings"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
 	ui "github.com/gizak/termui/v3"
 	"github.com/gizak/termui/v3/widgets"
 )
 
+// AddressKey holds the comma-separated addresses of the servers whose load
+// is set from the terminal. It may be left empty.
+const AddressKey = "ADDRESSES"
+
 type Simulation interface {
 	AddNeighbor()
 }
@@ -20,10 +28,18 @@ func main() {
 	var loadTextCallback = addLoadText()
 	var eventLoop, eventWriter = NewEventLoop()
 	eventLoop.SetLoadCallback(loadTextCallback)
+	var servers = connectServers(os.Getenv(AddressKey))
+	eventLoop.SetServers(servers)
 
 	var shutdown = addTextbox(eventWriter)
 
-	go addMachines()
+	// Servers are drawn on the machines in the order of their addresses.
+	var machines = addMachines()
+	for i, server := range servers {
+		if i < len(machines) {
+			go watchServer(server, machines[i])
+		}
+	}
 
 	// First, we create a list of machines.
 	// Each machine has at most one service.
@@ -32,14 +48,27 @@ func main() {
 
 	// Next, create a list of servers.
 
-	// Next, start a timer. Every second, we're going to ping each
-	// server and get it's result.
-	// We write that result to the widget responsible for this machine.
+	// Each server streams its throughput every second, and its state
+	// whenever it changes, to the widget responsible for its machine.
 
 	// Create a variable to track the current load.
 	<-shutdown
 }
 
+// connectServers returns a client for each of the comma-separated addresses.
+func connectServers(addresses string) []*api.Client {
+	var servers []*api.Client
+	for _, addr := range strings.Split(addresses, ",") {
+		if addr == "" {
+			continue
+		}
+		var server, err = api.NewClient(addr)
+		ExitOnError(err)
+		servers = append(servers, server)
+	}
+	return servers
+}
+
 func addTextbox(callback func(string)) <-chan struct{} {
 	// Add a textbox.
 	area := NewTextArea()
@@ -64,7 +93,7 @@ func addLoadText() func(uint64) {
 	}
 }
 
-func addMachines() {
+func addMachines() []*Machine {
 	var width, height = ui.TerminalDimensions()
 	var startHeight = 4
 	var endHeight = 4 + 3*height/10
@@ -81,6 +110,7 @@ func addMachines() {
 	ui.Render(machine1)
 	ui.Render(machine2)
 	ui.Render(machine3)
+	return []*Machine{machine1, machine2, machine3}
 }
 
 var nodeTmpl = `Addr: %v
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
+)
+
+const (
+	// sampleInterval is how often each server sends a throughput sample.
+	sampleInterval = time.Second
+	// resubscribeDelay is how long to wait before resubscribing to a server
+	// whose stream failed. Streams which end cleanly are resubscribed at once.
+	resubscribeDelay = time.Second
+)
+
+// watchServer subscribes to the server's stream of events, drawing each one on the machine.
+// Before each subscription, the machine's sparkline is backfilled from the
+// server's history, so nothing is missing after a reconnect.
+// It resubscribes whenever the stream ends, so it never returns.
+func watchServer(server *api.Client, machine *Machine) {
+	for {
+		var ctx, cancel = context.WithTimeout(context.Background(), requestTimeout)
+		var history, err = server.History(ctx, sampleInterval, time.Now().Add(-maxSamples*sampleInterval), time.Time{})
+		cancel()
+		if err == nil {
+			machine.Backfill(history.Points)
+		}
+		err = server.Stream(context.Background(), sampleInterval, func(event api.StreamEvent) {
+			switch {
+			case event.State != nil:
+				machine.ObserveState(*event.State)
+			case event.Throughput != nil:
+				machine.ObserveThroughput(*event.Throughput)
+			}
+		})
+		if err != nil {
+			machine.ObserveError(fmt.Errorf("%s: %v", server.Address(), err))
+			time.Sleep(resubscribeDelay)
+		}
+	}
+}
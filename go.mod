@@ -4,5 +4,8 @@ go 1.14
 
 require (
 	github.com/gizak/termui/v3 v3.1.0
+	github.com/hashicorp/hcl v1.0.0
 	github.com/hashicorp/nomad/api v0.0.0-20200807223033-5da78b72da73
+	google.golang.org/grpc v1.43.0
+	google.golang.org/protobuf v1.27.1
 )
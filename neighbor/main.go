@@ -1,100 +1,181 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"io/ioutil"
 	"log"
-	"net/http"
-	"net/url"
 	"os"
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
 )
 
 const (
 	LifetimeKey = "LIFETIME"
 	CPUKey      = "CPU"
 	AddressKey  = "ADDRESSES"
+	LeaseTTLKey = "LEASE_TTL"
+	LabelKey    = "LABEL"
+	QoSKey      = "QOS"
+	// MemoryBandwidthKey, DiskIOKey and NetworkKey are, like CPU, percentages
+	// of each resource to steal. Any of the four may be left unset.
+	MemoryBandwidthKey = "MEMORY_BANDWIDTH"
+	DiskIOKey          = "DISK_IO"
+	NetworkKey         = "NETWORK"
+	// RetriesKey is how many times to retry a steal the server rejected.
+	RetriesKey = "ADMISSION_RETRIES"
+	// BackoffKey is how many seconds to wait between those retries.
+	BackoffKey = "ADMISSION_BACKOFF"
+	// JobIDKey is set by Nomad to the ID of the job running this neighbor.
+	JobIDKey = "NOMAD_JOB_ID"
 )
 
+// resourceKeys maps the environment variable holding how much of each
+// resource to steal to the field of the request which carries it.
+var resourceKeys = map[string]func(*api.Resources) *uint64{
+	CPUKey:             func(r *api.Resources) *uint64 { return &r.CPU },
+	MemoryBandwidthKey: func(r *api.Resources) *uint64 { return &r.MemoryBandwidth },
+	DiskIOKey:          func(r *api.Resources) *uint64 { return &r.DiskIO },
+	NetworkKey:         func(r *api.Resources) *uint64 { return &r.Network },
+}
+
+// defaultLeaseTTL is the lease duration requested when LEASE_TTL is unset.
+const defaultLeaseTTL = "10"
+
+// defaultBackoff is the number of seconds between retries when ADMISSION_BACKOFF is unset.
+const defaultBackoff = "5"
+
+// ExitRejected is the exit code used when a server keeps rejecting this
+// neighbor, so that Nomad and the demo can tell it apart from a crash.
+const ExitRejected = 3
+
 func main() {
 	// Fetch the duration for which this "noisy neighbor" will
-	// steal CPU.
+	// steal resources.
 	var lifetimeStr = os.Getenv(LifetimeKey)
-	// Fetch the amount of CPU this workload will steal.
-	var cpuStr = os.Getenv(CPUKey)
+	// Fetch the amount of each resource this workload will steal.
+	var resources api.Resources
+	for key, field := range resourceKeys {
+		if value := os.Getenv(key); value != "" {
+			*field(&resources) = parseCPU(value)
+		}
+	}
 	// Fetch the server addresses which this workload will steal from.
 	var addressesStr = os.Getenv(AddressKey)
+	// Fetch how long each lease lasts without being renewed.
+	var ttlStr = os.Getenv(LeaseTTLKey)
 
 	if lifetimeStr == "" {
 		log.Fatalf("Expected non-empty lifetime")
 	}
-	if cpuStr == "" {
-		log.Fatalf("Expected non-empty CPU requirement")
+	if resources == (api.Resources{}) {
+		log.Fatalf("Expected at least one of %v, %v, %v or %v", CPUKey, MemoryBandwidthKey, DiskIOKey, NetworkKey)
 	}
 	if addressesStr == "" {
 		log.Fatalf("Expected non-empty address list")
 	}
+	if ttlStr == "" {
+		ttlStr = defaultLeaseTTL
+	}
+	var retries uint64
+	if retriesStr := os.Getenv(RetriesKey); retriesStr != "" {
+		var err error
+		if retries, err = strconv.ParseUint(retriesStr, 10, 64); err != nil {
+			log.Fatalf("Expected %v to be a non-negative number of retries: %v", RetriesKey, err)
+		}
+	}
+	var backoffStr = os.Getenv(BackoffKey)
+	if backoffStr == "" {
+		backoffStr = defaultBackoff
+	}
 
-	// var cpu = parseCPU(cpuStr)
 	var lifetime = parseLifetime(lifetimeStr)
 	var addresses = parseAddresses(addressesStr)
+	var ttl = parseLifetime(ttlStr)
+	if ttl <= 0 {
+		log.Fatalf("Expected %v to be a positive number of seconds, got %q", LeaseTTLKey, ttlStr)
+	}
+	var backoff = parseLifetime(backoffStr)
 
 	// Now, ping each address and add this service as a neighbor.
+	// Each server hands back a lease, which we hold onto so we can renew it.
+	var ctx = context.Background()
+	var leases = make(map[*api.Client]string, len(addresses))
+	var request = api.NeighborRequest{
+		Resources:  resources,
+		TTLSeconds: uint64(ttl / time.Second),
+		Label:      os.Getenv(LabelKey),
+		JobID:      os.Getenv(JobIDKey),
+		QoS:        os.Getenv(QoSKey),
+	}
 	for _, addr := range addresses {
-		var uri *url.URL
-		var resp *http.Response
-		var err error
-		uri, err = url.Parse(addr)
+		var client, err = api.NewClient(addr)
 		ExitOnError(err)
-		uri.Path = "/neighbors/add"
-		var params = uri.Query()
-		params.Set("cpu", cpuStr)
-		uri.RawQuery = params.Encode()
-		resp, err = http.Get(uri.String())
-		ExitOnError(err)
-		if resp.StatusCode != 200 {
-			defer resp.Body.Close()
-			body, err := ioutil.ReadAll(resp.Body)
-			ExitOnError(err)
-			log.Fatal(body)
+		lease, admitted := addNeighbor(ctx, client, request, retries, backoff)
+		if !admitted {
+			// Give back what we stole from the other servers before giving up.
+			for client, id := range leases {
+				_, err = client.RemoveNeighbor(ctx, id)
+				ExitOnError(err)
+			}
+			os.Exit(ExitRejected)
+		}
+		leases[client] = lease.Neighbor.ID
+	}
+	// Now, this batch job works for the specified duration.
+	// The time spent represents the duration for which this process is working.
+	// While we work, we renew our leases well before they expire. If we die,
+	// the leases lapse and the servers restore the resources on their own.
+	var done = time.After(lifetime)
+	var renew = time.NewTicker(ttl / 3)
+	defer renew.Stop()
+work:
+	for {
+		select {
+		case <-done:
+			break work
+		case <-renew.C:
+			for client, id := range leases {
+				var _, err = client.RenewNeighbor(ctx, id, ttl)
+				ExitOnError(err)
+			}
 		}
 	}
-	// Now, this batch job sleeps for the specified duration.
-	// The time slept represents the duration for which this process is working.
-	// When we awake, we'll restore the CPU to the process we stole from.
-	time.Sleep(lifetime)
 
-	// Finally, ping each address and add this service as a neighbor.
-	// TODO: Refactor this into a function which just takes the path.
-	for _, addr := range addresses {
-		var uri *url.URL
-		var resp *http.Response
-		var err error
-		uri, err = url.Parse(addr)
-		ExitOnError(err)
-		uri.Path = "/neighbors/remove"
-		var params = uri.Query()
-		params.Set("cpu", cpuStr)
-		uri.RawQuery = params.Encode()
-		resp, err = http.Get(uri.String())
+	// Finally, ping each address and release our lease, restoring the resources we stole.
+	for client, id := range leases {
+		var _, err = client.RemoveNeighbor(ctx, id)
 		ExitOnError(err)
-		if resp.StatusCode != 200 {
-			defer resp.Body.Close()
-			body, err := ioutil.ReadAll(resp.Body)
+	}
+}
+
+// addNeighbor steals resources from the server behind the client. If the server
+// rejects the steal because it would exceed its admission ceiling, the steal is
+// retried after the backoff, up to the given number of retries.
+// It returns false if the server never admitted this neighbor.
+func addNeighbor(ctx context.Context, client *api.Client, request api.NeighborRequest, retries uint64, backoff time.Duration) (api.NeighborAdded, bool) {
+	for attempt := uint64(0); ; attempt++ {
+		var added, err = client.AddNeighbor(ctx, request)
+		if !api.IsAdmissionRejected(err) {
 			ExitOnError(err)
-			log.Fatal(body)
+			return added, true
+		}
+		log.Printf("Rejected by %v: %v", client.Address(), err)
+		if attempt >= retries {
+			return api.NeighborAdded{}, false
 		}
+		time.Sleep(backoff)
 	}
 }
 
-// CPU requirements are presented as a number in the range [0…100]
-// We simply need to parse this int to get the CPU.
+// CPU and other resource requirements are presented as a number in the range [0…100]
+// We simply need to parse this int to get the amount.
 func parseCPU(cpu string) uint64 {
-	var i, err = strconv.ParseInt(cpu, 10, 64)
+	var i, err = strconv.ParseUint(cpu, 10, 64)
 	ExitOnError(err)
-	return uint64(i)
+	return i
 }
 
 // A lifetime is an integer representing the amount of time this
@@ -0,0 +1,441 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package rpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// SimulationClient is the client API for Simulation service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type SimulationClient interface {
+	// Health reports whether the service is alive under the load.
+	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
+	// Throughput returns the requests completed per second under the load.
+	Throughput(ctx context.Context, in *ThroughputRequest, opts ...grpc.CallOption) (*ThroughputResponse, error)
+	// GetLimits returns the capacity limits, before and after noisy neighbors are accounted for.
+	GetLimits(ctx context.Context, in *GetLimitsRequest, opts ...grpc.CallOption) (*Limits, error)
+	// UpdateLimits changes the capacity limits. Limits left unset keep their value.
+	// A soft limit above the hard limit, or a max throughput of 0, is INVALID_ARGUMENT.
+	UpdateLimits(ctx context.Context, in *UpdateLimitsRequest, opts ...grpc.CallOption) (*Limits, error)
+	// AddNeighbor steals resources, held by a lease until it's removed or expires.
+	// A neighbor which would exceed the ceiling of its QoS class is RESOURCE_EXHAUSTED.
+	AddNeighbor(ctx context.Context, in *AddNeighborRequest, opts ...grpc.CallOption) (*AddNeighborResponse, error)
+	// RenewNeighbor extends a lease. An unknown ID is NOT_FOUND.
+	RenewNeighbor(ctx context.Context, in *RenewNeighborRequest, opts ...grpc.CallOption) (*Neighbor, error)
+	// RemoveNeighbor releases a lease, restoring the resources it held. An unknown ID is NOT_FOUND.
+	RemoveNeighbor(ctx context.Context, in *RemoveNeighborRequest, opts ...grpc.CallOption) (*RemoveNeighborResponse, error)
+	// ListNeighbors returns every noisy neighbor holding a lease, oldest first.
+	ListNeighbors(ctx context.Context, in *ListNeighborsRequest, opts ...grpc.CallOption) (*ListNeighborsResponse, error)
+	// WatchState sends the state of the service straight away, and again
+	// whenever it changes, until the client goes away.
+	WatchState(ctx context.Context, in *WatchStateRequest, opts ...grpc.CallOption) (Simulation_WatchStateClient, error)
+}
+
+type simulationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewSimulationClient(cc grpc.ClientConnInterface) SimulationClient {
+	return &simulationClient{cc}
+}
+
+func (c *simulationClient) Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error) {
+	out := new(HealthResponse)
+	err := c.cc.Invoke(ctx, "/simulation.v1.Simulation/Health", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationClient) Throughput(ctx context.Context, in *ThroughputRequest, opts ...grpc.CallOption) (*ThroughputResponse, error) {
+	out := new(ThroughputResponse)
+	err := c.cc.Invoke(ctx, "/simulation.v1.Simulation/Throughput", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationClient) GetLimits(ctx context.Context, in *GetLimitsRequest, opts ...grpc.CallOption) (*Limits, error) {
+	out := new(Limits)
+	err := c.cc.Invoke(ctx, "/simulation.v1.Simulation/GetLimits", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationClient) UpdateLimits(ctx context.Context, in *UpdateLimitsRequest, opts ...grpc.CallOption) (*Limits, error) {
+	out := new(Limits)
+	err := c.cc.Invoke(ctx, "/simulation.v1.Simulation/UpdateLimits", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationClient) AddNeighbor(ctx context.Context, in *AddNeighborRequest, opts ...grpc.CallOption) (*AddNeighborResponse, error) {
+	out := new(AddNeighborResponse)
+	err := c.cc.Invoke(ctx, "/simulation.v1.Simulation/AddNeighbor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationClient) RenewNeighbor(ctx context.Context, in *RenewNeighborRequest, opts ...grpc.CallOption) (*Neighbor, error) {
+	out := new(Neighbor)
+	err := c.cc.Invoke(ctx, "/simulation.v1.Simulation/RenewNeighbor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationClient) RemoveNeighbor(ctx context.Context, in *RemoveNeighborRequest, opts ...grpc.CallOption) (*RemoveNeighborResponse, error) {
+	out := new(RemoveNeighborResponse)
+	err := c.cc.Invoke(ctx, "/simulation.v1.Simulation/RemoveNeighbor", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationClient) ListNeighbors(ctx context.Context, in *ListNeighborsRequest, opts ...grpc.CallOption) (*ListNeighborsResponse, error) {
+	out := new(ListNeighborsResponse)
+	err := c.cc.Invoke(ctx, "/simulation.v1.Simulation/ListNeighbors", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *simulationClient) WatchState(ctx context.Context, in *WatchStateRequest, opts ...grpc.CallOption) (Simulation_WatchStateClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Simulation_ServiceDesc.Streams[0], "/simulation.v1.Simulation/WatchState", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &simulationWatchStateClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Simulation_WatchStateClient interface {
+	Recv() (*State, error)
+	grpc.ClientStream
+}
+
+type simulationWatchStateClient struct {
+	grpc.ClientStream
+}
+
+func (x *simulationWatchStateClient) Recv() (*State, error) {
+	m := new(State)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// SimulationServer is the server API for Simulation service.
+// All implementations must embed UnimplementedSimulationServer
+// for forward compatibility
+type SimulationServer interface {
+	// Health reports whether the service is alive under the load.
+	Health(context.Context, *HealthRequest) (*HealthResponse, error)
+	// Throughput returns the requests completed per second under the load.
+	Throughput(context.Context, *ThroughputRequest) (*ThroughputResponse, error)
+	// GetLimits returns the capacity limits, before and after noisy neighbors are accounted for.
+	GetLimits(context.Context, *GetLimitsRequest) (*Limits, error)
+	// UpdateLimits changes the capacity limits. Limits left unset keep their value.
+	// A soft limit above the hard limit, or a max throughput of 0, is INVALID_ARGUMENT.
+	UpdateLimits(context.Context, *UpdateLimitsRequest) (*Limits, error)
+	// AddNeighbor steals resources, held by a lease until it's removed or expires.
+	// A neighbor which would exceed the ceiling of its QoS class is RESOURCE_EXHAUSTED.
+	AddNeighbor(context.Context, *AddNeighborRequest) (*AddNeighborResponse, error)
+	// RenewNeighbor extends a lease. An unknown ID is NOT_FOUND.
+	RenewNeighbor(context.Context, *RenewNeighborRequest) (*Neighbor, error)
+	// RemoveNeighbor releases a lease, restoring the resources it held. An unknown ID is NOT_FOUND.
+	RemoveNeighbor(context.Context, *RemoveNeighborRequest) (*RemoveNeighborResponse, error)
+	// ListNeighbors returns every noisy neighbor holding a lease, oldest first.
+	ListNeighbors(context.Context, *ListNeighborsRequest) (*ListNeighborsResponse, error)
+	// WatchState sends the state of the service straight away, and again
+	// whenever it changes, until the client goes away.
+	WatchState(*WatchStateRequest, Simulation_WatchStateServer) error
+	mustEmbedUnimplementedSimulationServer()
+}
+
+// UnimplementedSimulationServer must be embedded to have forward compatible implementations.
+type UnimplementedSimulationServer struct {
+}
+
+func (UnimplementedSimulationServer) Health(context.Context, *HealthRequest) (*HealthResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Health not implemented")
+}
+func (UnimplementedSimulationServer) Throughput(context.Context, *ThroughputRequest) (*ThroughputResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Throughput not implemented")
+}
+func (UnimplementedSimulationServer) GetLimits(context.Context, *GetLimitsRequest) (*Limits, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLimits not implemented")
+}
+func (UnimplementedSimulationServer) UpdateLimits(context.Context, *UpdateLimitsRequest) (*Limits, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateLimits not implemented")
+}
+func (UnimplementedSimulationServer) AddNeighbor(context.Context, *AddNeighborRequest) (*AddNeighborResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddNeighbor not implemented")
+}
+func (UnimplementedSimulationServer) RenewNeighbor(context.Context, *RenewNeighborRequest) (*Neighbor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RenewNeighbor not implemented")
+}
+func (UnimplementedSimulationServer) RemoveNeighbor(context.Context, *RemoveNeighborRequest) (*RemoveNeighborResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveNeighbor not implemented")
+}
+func (UnimplementedSimulationServer) ListNeighbors(context.Context, *ListNeighborsRequest) (*ListNeighborsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNeighbors not implemented")
+}
+func (UnimplementedSimulationServer) WatchState(*WatchStateRequest, Simulation_WatchStateServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchState not implemented")
+}
+func (UnimplementedSimulationServer) mustEmbedUnimplementedSimulationServer() {}
+
+// UnsafeSimulationServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to SimulationServer will
+// result in compilation errors.
+type UnsafeSimulationServer interface {
+	mustEmbedUnimplementedSimulationServer()
+}
+
+func RegisterSimulationServer(s grpc.ServiceRegistrar, srv SimulationServer) {
+	s.RegisterService(&Simulation_ServiceDesc, srv)
+}
+
+func _Simulation_Health_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HealthRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServer).Health(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/simulation.v1.Simulation/Health",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServer).Health(ctx, req.(*HealthRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulation_Throughput_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ThroughputRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServer).Throughput(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/simulation.v1.Simulation/Throughput",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServer).Throughput(ctx, req.(*ThroughputRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulation_GetLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServer).GetLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/simulation.v1.Simulation/GetLimits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServer).GetLimits(ctx, req.(*GetLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulation_UpdateLimits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateLimitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServer).UpdateLimits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/simulation.v1.Simulation/UpdateLimits",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServer).UpdateLimits(ctx, req.(*UpdateLimitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulation_AddNeighbor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddNeighborRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServer).AddNeighbor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/simulation.v1.Simulation/AddNeighbor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServer).AddNeighbor(ctx, req.(*AddNeighborRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulation_RenewNeighbor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewNeighborRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServer).RenewNeighbor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/simulation.v1.Simulation/RenewNeighbor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServer).RenewNeighbor(ctx, req.(*RenewNeighborRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulation_RemoveNeighbor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveNeighborRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServer).RemoveNeighbor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/simulation.v1.Simulation/RemoveNeighbor",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServer).RemoveNeighbor(ctx, req.(*RemoveNeighborRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulation_ListNeighbors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNeighborsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SimulationServer).ListNeighbors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/simulation.v1.Simulation/ListNeighbors",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SimulationServer).ListNeighbors(ctx, req.(*ListNeighborsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Simulation_WatchState_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchStateRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SimulationServer).WatchState(m, &simulationWatchStateServer{stream})
+}
+
+type Simulation_WatchStateServer interface {
+	Send(*State) error
+	grpc.ServerStream
+}
+
+type simulationWatchStateServer struct {
+	grpc.ServerStream
+}
+
+func (x *simulationWatchStateServer) Send(m *State) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// Simulation_ServiceDesc is the grpc.ServiceDesc for Simulation service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Simulation_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "simulation.v1.Simulation",
+	HandlerType: (*SimulationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Health",
+			Handler:    _Simulation_Health_Handler,
+		},
+		{
+			MethodName: "Throughput",
+			Handler:    _Simulation_Throughput_Handler,
+		},
+		{
+			MethodName: "GetLimits",
+			Handler:    _Simulation_GetLimits_Handler,
+		},
+		{
+			MethodName: "UpdateLimits",
+			Handler:    _Simulation_UpdateLimits_Handler,
+		},
+		{
+			MethodName: "AddNeighbor",
+			Handler:    _Simulation_AddNeighbor_Handler,
+		},
+		{
+			MethodName: "RenewNeighbor",
+			Handler:    _Simulation_RenewNeighbor_Handler,
+		},
+		{
+			MethodName: "RemoveNeighbor",
+			Handler:    _Simulation_RemoveNeighbor_Handler,
+		},
+		{
+			MethodName: "ListNeighbors",
+			Handler:    _Simulation_ListNeighbors_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchState",
+			Handler:       _Simulation_WatchState_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "rpc/simulation.proto",
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
+)
+
+// A QoS class decides how much headroom a noisy neighbor is admitted into.
+// Lower classes can only steal CPU while plenty is left, which keeps
+// headroom free for the higher classes.
+type QoS string
+
+const (
+	QoSGuaranteed QoS = "guaranteed"
+	QoSBurstable  QoS = "burstable"
+	QoSBestEffort QoS = "best-effort"
+)
+
+// parseQoS returns the QoS class with the given name.
+// Neighbors which don't name a class are guaranteed.
+func parseQoS(name string) (QoS, error) {
+	switch QoS(name) {
+	case "", QoSGuaranteed:
+		return QoSGuaranteed, nil
+	case QoSBurstable, QoSBestEffort:
+		return QoS(name), nil
+	default:
+		return "", fmt.Errorf("unknown QoS class %q", name)
+	}
+}
+
+// An AdmissionPolicy caps the total of each resource, as a percentage, which
+// noisy neighbors of each QoS class may bring the stolen resources up to.
+// The same ceilings apply to every resource.
+type AdmissionPolicy struct {
+	MaxStolen           uint64
+	BurstableMaxStolen  uint64
+	BestEffortMaxStolen uint64
+}
+
+// DefaultAdmissionPolicy never lets neighbors steal more than all of a resource,
+// and keeps a quarter of it free of burstable and half free of best-effort neighbors.
+func DefaultAdmissionPolicy() AdmissionPolicy {
+	return AdmissionPolicy{
+		MaxStolen:           100,
+		BurstableMaxStolen:  75,
+		BestEffortMaxStolen: 50,
+	}
+}
+
+// Ceiling returns the most of any resource which may be stolen once a neighbor
+// of the given class is admitted.
+func (policy AdmissionPolicy) Ceiling(qos QoS) uint64 {
+	var ceiling = policy.MaxStolen
+	switch qos {
+	case QoSBurstable:
+		ceiling = minUint(ceiling, policy.BurstableMaxStolen)
+	case QoSBestEffort:
+		ceiling = minUint(ceiling, policy.BestEffortMaxStolen)
+	}
+	return ceiling
+}
+
+func minUint(a, b uint64) uint64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// An AdmissionError explains why a noisy neighbor was turned away.
+type AdmissionError struct {
+	QoS       QoS
+	Resource  Resource
+	Requested uint64
+	Stolen    uint64
+	Ceiling   uint64
+}
+
+func (err *AdmissionError) Error() string {
+	return fmt.Sprintf("admitting %d%% %s for a %s neighbor would exceed its ceiling of %d%% (already stolen: %d%%)",
+		err.Requested, err.Resource, err.QoS, err.Ceiling, err.Stolen)
+}
+
+// toV1 converts the error to its /v1 representation.
+func (err *AdmissionError) toV1() *api.AdmissionRejection {
+	return &api.AdmissionRejection{
+		QoS:       string(err.QoS),
+		Resource:  string(err.Resource),
+		Requested: err.Requested,
+		Stolen:    err.Stolen,
+		Ceiling:   err.Ceiling,
+	}
+}
+
+// writeAdmissionError responds with a 409 describing the rejected steal.
+func writeAdmissionError(w http.ResponseWriter, err *AdmissionError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusConflict)
+	var encoder = json.NewEncoder(w)
+	var responseBody = AdmissionRejectedResponse{
+		Error:     err.Error(),
+		QoS:       string(err.QoS),
+		Resource:  string(err.Resource),
+		Requested: err.Requested,
+		Stolen:    err.Stolen,
+		Ceiling:   err.Ceiling,
+	}
+	// The status has already been written, so an encoding error can't be reported.
+	_ = encoder.Encode(responseBody)
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAdmissionPolicyCeiling(t *testing.T) {
+	var policy = AdmissionPolicy{MaxStolen: 90, BurstableMaxStolen: 75, BestEffortMaxStolen: 95}
+	var tests = []struct {
+		qos  QoS
+		want uint64
+	}{
+		{QoSGuaranteed, 90},
+		{QoSBurstable, 75},
+		// A lower class never gets more headroom than the guaranteed one.
+		{QoSBestEffort, 90},
+	}
+	for _, test := range tests {
+		if got := policy.Ceiling(test.qos); got != test.want {
+			t.Errorf("Ceiling(%s) = %d, want %d", test.qos, got, test.want)
+		}
+	}
+}
+
+func TestAddNeighborAdmission(t *testing.T) {
+	var tests = []struct {
+		name string
+		// stolen is taken by a guaranteed neighbor before the one under test.
+		stolen    Resources
+		qos       QoS
+		resources Resources
+		// rejected names the resource over the ceiling, if the neighbor is turned away.
+		rejected Resource
+		ceiling  uint64
+	}{
+		{name: "guaranteed up to all the CPU", stolen: Resources{CPU: 60}, qos: QoSGuaranteed, resources: Resources{CPU: 40}},
+		{name: "guaranteed past all the CPU", stolen: Resources{CPU: 60}, qos: QoSGuaranteed, resources: Resources{CPU: 41}, rejected: ResourceCPU, ceiling: 100},
+		{name: "burstable up to its ceiling", stolen: Resources{CPU: 60}, qos: QoSBurstable, resources: Resources{CPU: 15}},
+		{name: "burstable past its ceiling", stolen: Resources{CPU: 60}, qos: QoSBurstable, resources: Resources{CPU: 16}, rejected: ResourceCPU, ceiling: 75},
+		{name: "best-effort up to its ceiling", stolen: Resources{CPU: 30}, qos: QoSBestEffort, resources: Resources{CPU: 20}},
+		{name: "best-effort past its ceiling", stolen: Resources{CPU: 30}, qos: QoSBestEffort, resources: Resources{CPU: 21}, rejected: ResourceCPU, ceiling: 50},
+		{name: "best-effort network past its ceiling", stolen: Resources{Network: 45}, qos: QoSBestEffort, resources: Resources{Network: 10}, rejected: ResourceNetwork, ceiling: 50},
+		{name: "burstable disk I/O past its ceiling", qos: QoSBurstable, resources: Resources{CPU: 10, DiskIO: 80}, rejected: ResourceDiskIO, ceiling: 75},
+		// Only the resources a neighbor steals are held to the ceiling.
+		{name: "other resources past the ceiling", stolen: Resources{MemoryBandwidth: 90}, qos: QoSBestEffort, resources: Resources{CPU: 10}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var service = NewSimulatedService(1000, 1500, 2000)
+			defer service.Close()
+			if !test.stolen.IsZero() {
+				if _, _, err := service.AddNeighbor(Neighbor{Resources: test.stolen, QoS: QoSGuaranteed}, defaultLeaseTTL); err != nil {
+					t.Fatalf("AddNeighbor() for what's already stolen = %v", err)
+				}
+			}
+			var neighbor, previous, err = service.AddNeighbor(Neighbor{Resources: test.resources, QoS: test.qos}, defaultLeaseTTL)
+			if previous != test.stolen {
+				t.Errorf("AddNeighbor() previously stolen = %+v, want %+v", previous, test.stolen)
+			}
+			if test.rejected == "" {
+				if err != nil {
+					t.Fatalf("AddNeighbor() = %v, want the neighbor admitted", err)
+				}
+				if want := test.stolen.Add(test.resources); service.Stolen() != want {
+					t.Errorf("Stolen() = %+v, want %+v", service.Stolen(), want)
+				}
+				if _, ok := service.Neighbor(neighbor.ID); !ok {
+					t.Errorf("the admitted neighbor %q isn't registered", neighbor.ID)
+				}
+				return
+			}
+			var rejection, ok = err.(*AdmissionError)
+			if !ok {
+				t.Fatalf("AddNeighbor() = %v, want an *AdmissionError", err)
+			}
+			if rejection.QoS != test.qos || rejection.Resource != test.rejected || rejection.Ceiling != test.ceiling ||
+				rejection.Requested != test.resources.Get(test.rejected) || rejection.Stolen != test.stolen.Get(test.rejected) {
+				t.Errorf("AddNeighbor() = %+v, want a rejection of %s at a ceiling of %d", rejection, test.rejected, test.ceiling)
+			}
+			if service.Stolen() != test.stolen {
+				t.Errorf("Stolen() = %+v after the rejection, want %+v unchanged", service.Stolen(), test.stolen)
+			}
+			var registered = 0
+			if !test.stolen.IsZero() {
+				registered = 1
+			}
+			if got := len(service.Neighbors()); got != registered {
+				t.Errorf("%d neighbors registered after the rejection, want %d", got, registered)
+			}
+		})
+	}
+}
+
+func TestAddNeighborRejectionResponds409(t *testing.T) {
+	var requests = []struct {
+		method, target, body string
+	}{
+		{"POST", "/v1/neighbors", `{"cpu": 60, "qos": "best-effort"}`},
+		{"GET", "/neighbors/add?cpu=60&qos=best-effort", ""},
+	}
+	for _, request := range requests {
+		var service = NewSimulatedService(1000, 1500, 2000)
+		defer service.Close()
+		var recorder = httptest.NewRecorder()
+		service.ServeHTTP(recorder, httptest.NewRequest(request.method, request.target, strings.NewReader(request.body)))
+		if recorder.Code != http.StatusConflict {
+			t.Errorf("%s %s = %d, want %d", request.method, request.target, recorder.Code, http.StatusConflict)
+		}
+		if !service.Stolen().IsZero() || len(service.Neighbors()) != 0 {
+			t.Errorf("%s %s stole %+v, want nothing stolen", request.method, request.target, service.Stolen())
+		}
+	}
+}
@@ -0,0 +1,453 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl"
+)
+
+const (
+	// envPrefix starts the name of every environment variable read by the server.
+	envPrefix = "SIMULATION_"
+	// configFileKey is the environment variable naming the config file.
+	configFileKey = envPrefix + "CONFIG"
+	// portLabelKey is the environment variable naming the Nomad port label
+	// whose NOMAD_ADDR_<label> or NOMAD_PORT_<label> the server listens on.
+	portLabelKey     = envPrefix + "PORT_LABEL"
+	defaultPortLabel = "http"
+)
+
+// Config holds every setting of the server.
+//
+// Settings are applied in order of increasing precedence:
+// 1. The defaults.
+// 2. The config file, in HCL or JSON, named by -config or $SIMULATION_CONFIG.
+// 3. Nomad's NOMAD_ADDR_<label> or NOMAD_PORT_<label>, for the listen address.
+// 4. SIMULATION_* environment variables, e.g. $SIMULATION_MAX_THROUGHPUT.
+// 5. Command line flags, e.g. -max-throughput.
+type Config struct {
+	Listen string
+	// GRPCListen is the address the gRPC API listens on. Empty disables it.
+	GRPCListen string
+
+	MaxThroughput,
+	SoftLimit,
+	HardLimit uint64
+
+	// Mode is simulated, or real to burn CPU on /v1/work and measure the throughput.
+	Mode string
+	// Workers, WorkQueue and WorkCPU size the work pool in real mode.
+	Workers   uint64
+	WorkQueue uint64
+	WorkCPU   time.Duration
+
+	Model       string
+	Servers     uint64
+	QueueLength uint64
+	Contention  float64
+	Coherency   float64
+
+	// CPUSource is simulated, measured or blended: where the available CPU comes from.
+	CPUSource string
+	// CPUBlend is the weight of the measured CPU when the source is blended.
+	CPUBlend float64
+
+	CPUSensitivity             float64
+	MemoryBandwidthSensitivity float64
+	DiskIOSensitivity          float64
+	NetworkSensitivity         float64
+
+	// Seed seeds the simulation's randomness. Zero seeds it with the time.
+	Seed int64
+
+	StrictHealth bool
+	CrashAfter   time.Duration
+	DeathSpiral  time.Duration
+	WarmUp       time.Duration
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	// DrainDelay is how long the service reports not ready before shutting down.
+	DrainDelay time.Duration
+	// ShutdownTimeout is how long in-flight requests get to finish on shutdown.
+	ShutdownTimeout time.Duration
+
+	// MaxStolen, BurstableMaxStolen and BestEffortMaxStolen are the admission
+	// ceilings of each QoS class, as a percentage of every resource.
+	MaxStolen           uint64
+	BurstableMaxStolen  uint64
+	BestEffortMaxStolen uint64
+
+	// EventHistory is how many state changes are kept for GET /v1/events.
+	EventHistory uint64
+	// EventFile, if set, is appended every state change as a line of JSON.
+	EventFile string
+}
+
+// DefaultConfig returns the config used when nothing else is provided.
+func DefaultConfig() Config {
+	return Config{
+		Listen:          ":8080",
+		MaxThroughput:   startingThroughput,
+		SoftLimit:       startingSoft,
+		HardLimit:       startingHard,
+		Mode:            simulatedMode,
+		WorkQueue:       defaultWorkQueue,
+		WorkCPU:         defaultWorkCPU,
+		Model:           "step",
+		Servers:         4,
+		QueueLength:     100,
+		Contention:      0.05,
+		Coherency:       0.02,
+		ReadTimeout:     requestTimeout,
+		WriteTimeout:    requestTimeout,
+		ShutdownTimeout: defaultShutdownTimeout,
+
+		CPUSource: simulatedCPU,
+		CPUBlend:  defaultCPUBlend,
+
+		CPUSensitivity:             DefaultSensitivity().CPU,
+		MemoryBandwidthSensitivity: DefaultSensitivity().MemoryBandwidth,
+		DiskIOSensitivity:          DefaultSensitivity().DiskIO,
+		NetworkSensitivity:         DefaultSensitivity().Network,
+
+		MaxStolen:           DefaultAdmissionPolicy().MaxStolen,
+		BurstableMaxStolen:  DefaultAdmissionPolicy().BurstableMaxStolen,
+		BestEffortMaxStolen: DefaultAdmissionPolicy().BestEffortMaxStolen,
+
+		EventHistory: defaultEventHistory,
+	}
+}
+
+// A setting is a single entry in the config.
+// Its name is used as the flag name. Its environment variable and config
+// file key are derived from the name, e.g. max-throughput is read from
+// $SIMULATION_MAX_THROUGHPUT and the max_throughput key.
+type setting struct {
+	name   string
+	usage  string
+	get    func(config *Config) string
+	set    func(config *Config, value string) error
+	isBool bool
+	// reloadable settings are applied on SIGHUP, without a restart.
+	reloadable bool
+}
+
+// reloadOnHangup marks the setting as applied on SIGHUP, without a restart.
+func (s setting) reloadOnHangup() setting {
+	s.reloadable = true
+	return s
+}
+
+func (s setting) envKey() string {
+	return envPrefix + strings.ToUpper(strings.Replace(s.name, "-", "_", -1))
+}
+
+func (s setting) fileKey() string {
+	return strings.Replace(s.name, "-", "_", -1)
+}
+
+// settings lists every setting in the config.
+var settings = []setting{
+	stringSetting("listen", "address to listen on", func(c *Config) *string { return &c.Listen }),
+	stringSetting("grpc-listen", "address the gRPC API listens on (empty disables it)", func(c *Config) *string { return &c.GRPCListen }),
+	uintSetting("max-throughput", "requests per second processable without noisy neighbors", func(c *Config) *uint64 { return &c.MaxThroughput }).reloadOnHangup(),
+	uintSetting("soft-limit", "load at which the service starts to degrade", func(c *Config) *uint64 { return &c.SoftLimit }).reloadOnHangup(),
+	uintSetting("hard-limit", "load past which the service falls over", func(c *Config) *uint64 { return &c.HardLimit }).reloadOnHangup(),
+	stringSetting("mode", "simulated, or real to burn CPU on /v1/work and report the measured throughput", func(c *Config) *string { return &c.Mode }),
+	uintSetting("workers", "requests worked on at once in real mode (0 uses one per CPU)", func(c *Config) *uint64 { return &c.Workers }),
+	uintSetting("work-queue", "requests which may wait for a worker in real mode", func(c *Config) *uint64 { return &c.WorkQueue }),
+	durationSetting("work-cpu", "CPU time each request to /v1/work takes on an idle machine", func(c *Config) *time.Duration { return &c.WorkCPU }),
+	stringSetting("model", "throughput model: step, mm1, mmc, or usl", func(c *Config) *string { return &c.Model }).reloadOnHangup(),
+	uintSetting("servers", "number of workers in the mmc model", func(c *Config) *uint64 { return &c.Servers }).reloadOnHangup(),
+	uintSetting("queue-length", "requests held at once in the mm1 and mmc models", func(c *Config) *uint64 { return &c.QueueLength }).reloadOnHangup(),
+	floatSetting("contention", "contention (sigma) in the usl model", func(c *Config) *float64 { return &c.Contention }).reloadOnHangup(),
+	floatSetting("coherency", "coherency (kappa) in the usl model", func(c *Config) *float64 { return &c.Coherency }).reloadOnHangup(),
+	stringSetting("cpu-source", "where the available CPU comes from: simulated, measured from cgroups and /proc/stat on Linux, or blended", func(c *Config) *string { return &c.CPUSource }),
+	floatSetting("cpu-blend", "weight of the measured CPU, from 0 to 1, when the cpu source is blended", func(c *Config) *float64 { return &c.CPUBlend }),
+	floatSetting("cpu-sensitivity", "how strongly stolen CPU slows the service (1 is proportional)", func(c *Config) *float64 { return &c.CPUSensitivity }).reloadOnHangup(),
+	floatSetting("memory-bandwidth-sensitivity", "how strongly stolen memory bandwidth slows the service", func(c *Config) *float64 { return &c.MemoryBandwidthSensitivity }).reloadOnHangup(),
+	floatSetting("disk-io-sensitivity", "how strongly stolen disk I/O slows the service", func(c *Config) *float64 { return &c.DiskIOSensitivity }).reloadOnHangup(),
+	floatSetting("network-sensitivity", "how strongly stolen network bandwidth slows the service", func(c *Config) *float64 { return &c.NetworkSensitivity }).reloadOnHangup(),
+	intSetting("seed", "seed for the simulation's randomness (0 uses the time)", func(c *Config) *int64 { return &c.Seed }),
+	boolSetting("strict-health", "respond to /healthz with 503 when dead and 400 for bad input", func(c *Config) *bool { return &c.StrictHealth }),
+	durationSetting("crash-after", "exit once past the hard limit for this long (0 never exits)", func(c *Config) *time.Duration { return &c.CrashAfter }),
+	durationSetting("death-spiral", "how long to report being dead before exiting on overload", func(c *Config) *time.Duration { return &c.DeathSpiral }),
+	durationSetting("warm-up", "how long the service takes to reach full capacity after starting", func(c *Config) *time.Duration { return &c.WarmUp }),
+	durationSetting("read-timeout", "maximum duration for reading a request", func(c *Config) *time.Duration { return &c.ReadTimeout }),
+	durationSetting("write-timeout", "maximum duration for writing a response, and the latency of a dead service", func(c *Config) *time.Duration { return &c.WriteTimeout }),
+	durationSetting("drain-delay", "how long to report not ready on SIGINT or SIGTERM before shutting down", func(c *Config) *time.Duration { return &c.DrainDelay }),
+	durationSetting("shutdown-timeout", "how long in-flight requests get to finish on SIGINT or SIGTERM", func(c *Config) *time.Duration { return &c.ShutdownTimeout }),
+	uintSetting("max-stolen", "most of each resource, as a percentage, which noisy neighbors may steal", func(c *Config) *uint64 { return &c.MaxStolen }),
+	uintSetting("burstable-max-stolen", "most of each resource which may be stolen once a burstable neighbor is admitted", func(c *Config) *uint64 { return &c.BurstableMaxStolen }),
+	uintSetting("best-effort-max-stolen", "most of each resource which may be stolen once a best-effort neighbor is admitted", func(c *Config) *uint64 { return &c.BestEffortMaxStolen }),
+	uintSetting("event-history", "number of state changes kept for /v1/events", func(c *Config) *uint64 { return &c.EventHistory }),
+	stringSetting("event-file", "file to append every state change to, as JSON lines (empty disables it)", func(c *Config) *string { return &c.EventFile }),
+}
+
+func stringSetting(name, usage string, field func(*Config) *string) setting {
+	return setting{
+		name:  name,
+		usage: usage,
+		get:   func(c *Config) string { return *field(c) },
+		set: func(c *Config, value string) error {
+			*field(c) = value
+			return nil
+		},
+	}
+}
+
+func uintSetting(name, usage string, field func(*Config) *uint64) setting {
+	return setting{
+		name:  name,
+		usage: usage,
+		get:   func(c *Config) string { return strconv.FormatUint(*field(c), 10) },
+		set: func(c *Config, value string) (err error) {
+			*field(c), err = strconv.ParseUint(value, 10, 64)
+			return err
+		},
+	}
+}
+
+func intSetting(name, usage string, field func(*Config) *int64) setting {
+	return setting{
+		name:  name,
+		usage: usage,
+		get:   func(c *Config) string { return strconv.FormatInt(*field(c), 10) },
+		set: func(c *Config, value string) (err error) {
+			*field(c), err = strconv.ParseInt(value, 10, 64)
+			return err
+		},
+	}
+}
+
+func floatSetting(name, usage string, field func(*Config) *float64) setting {
+	return setting{
+		name:  name,
+		usage: usage,
+		get:   func(c *Config) string { return strconv.FormatFloat(*field(c), 'g', -1, 64) },
+		set: func(c *Config, value string) error {
+			var parsed, err = strconv.ParseFloat(value, 64)
+			if err != nil {
+				return err
+			}
+			// ParseFloat accepts NaN and infinities, which no setting can use.
+			if math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+				return fmt.Errorf("%q is not a finite number", value)
+			}
+			*field(c) = parsed
+			return nil
+		},
+	}
+}
+
+func boolSetting(name, usage string, field func(*Config) *bool) setting {
+	return setting{
+		name:  name,
+		usage: usage,
+		get:   func(c *Config) string { return strconv.FormatBool(*field(c)) },
+		set: func(c *Config, value string) (err error) {
+			*field(c), err = strconv.ParseBool(value)
+			return err
+		},
+		isBool: true,
+	}
+}
+
+func durationSetting(name, usage string, field func(*Config) *time.Duration) setting {
+	return setting{
+		name:  name,
+		usage: usage,
+		get:   func(c *Config) string { return field(c).String() },
+		set: func(c *Config, value string) (err error) {
+			*field(c), err = time.ParseDuration(value)
+			return err
+		},
+	}
+}
+
+// settingFlag adapts a setting to the flag.Value interface.
+type settingFlag struct {
+	setting
+	config *Config
+}
+
+func (f settingFlag) String() string {
+	if f.config == nil {
+		return ""
+	}
+	return f.get(f.config)
+}
+
+func (f settingFlag) Set(value string) error {
+	return f.set(f.config, value)
+}
+
+// IsBoolFlag lets boolean settings be passed as flags without a value.
+func (f settingFlag) IsBoolFlag() bool {
+	return f.isBool
+}
+
+// LoadConfig builds the config from the defaults, the config file,
+// the environment, and the given command line arguments.
+func LoadConfig(args []string) (Config, error) {
+	var config = DefaultConfig()
+
+	// Parse the flags into their own config, so we know which ones were set
+	// and can apply them last.
+	var flagConfig = DefaultConfig()
+	var flags = flag.NewFlagSet("server", flag.ContinueOnError)
+	var configFile = flags.String("config", os.Getenv(configFileKey), "path to an HCL or JSON config file (defaults to $"+configFileKey+")")
+	for _, s := range settings {
+		flags.Var(settingFlag{s, &flagConfig}, s.name, s.usage)
+	}
+	if err := flags.Parse(args); err != nil {
+		return config, err
+	}
+
+	if *configFile != "" {
+		if err := config.loadFile(*configFile); err != nil {
+			return config, err
+		}
+	}
+	config.loadNomadEnv()
+	for _, s := range settings {
+		var value, ok = os.LookupEnv(s.envKey())
+		if !ok {
+			continue
+		}
+		if err := s.set(&config, value); err != nil {
+			return config, fmt.Errorf("parsing $%s: %v", s.envKey(), err)
+		}
+	}
+	flags.Visit(func(f *flag.Flag) {
+		if setting, ok := f.Value.(settingFlag); ok {
+			setting.set(&config, setting.get(&flagConfig))
+		}
+	})
+	if config.Seed == 0 {
+		config.Seed = time.Now().UnixNano()
+	}
+	return config, config.Validate()
+}
+
+// loadFile applies the settings in the HCL or JSON file at the given path.
+func (config *Config) loadFile(path string) error {
+	var contents, err = ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var values map[string]interface{}
+	if err = hcl.Decode(&values, string(contents)); err != nil {
+		return fmt.Errorf("parsing %s: %v", path, err)
+	}
+	for _, s := range settings {
+		var value, ok = values[s.fileKey()]
+		if !ok {
+			continue
+		}
+		delete(values, s.fileKey())
+		if err = s.set(config, fmt.Sprint(value)); err != nil {
+			return fmt.Errorf("parsing %s in %s: %v", s.fileKey(), path, err)
+		}
+	}
+	for key := range values {
+		return fmt.Errorf("unknown setting %s in %s", key, path)
+	}
+	return nil
+}
+
+// loadNomadEnv listens on the address or port Nomad allocated to this task.
+func (config *Config) loadNomadEnv() {
+	var label = os.Getenv(portLabelKey)
+	if label == "" {
+		label = defaultPortLabel
+	}
+	if addr := os.Getenv("NOMAD_ADDR_" + label); addr != "" {
+		config.Listen = addr
+	} else if port := os.Getenv("NOMAD_PORT_" + label); port != "" {
+		config.Listen = ":" + port
+	}
+}
+
+// Validate returns an error if the config can't be used to run a service.
+func (config Config) Validate() error {
+	if config.Listen == "" {
+		return errors.New("the listen address must not be empty")
+	}
+	if err := validateLimits(config.MaxThroughput, config.SoftLimit, config.HardLimit); err != nil {
+		return err
+	}
+	if config.Mode != simulatedMode && config.Mode != realMode {
+		return fmt.Errorf("unknown mode %q, expected %s or %s", config.Mode, simulatedMode, realMode)
+	}
+	if config.WorkCPU <= 0 {
+		return errors.New("the work CPU must be greater than zero")
+	}
+	if config.CPUSource != simulatedCPU && config.CPUSource != measuredCPU && config.CPUSource != blendedCPU {
+		return fmt.Errorf("unknown cpu source %q, expected %s, %s or %s", config.CPUSource, simulatedCPU, measuredCPU, blendedCPU)
+	}
+	if config.CPUBlend < 0 || config.CPUBlend > 1 {
+		return errors.New("the cpu blend must be from 0 to 1")
+	}
+	if config.QueueLength > maxQueueSize || config.Servers > maxQueueSize {
+		return fmt.Errorf("the queue length and servers must be at most %d", maxQueueSize)
+	}
+	if _, err := config.ThroughputModel(); err != nil {
+		return err
+	}
+	if err := config.Sensitivity().Validate(); err != nil {
+		return err
+	}
+	if config.ReadTimeout <= 0 || config.WriteTimeout <= 0 || config.ShutdownTimeout <= 0 {
+		return errors.New("timeouts must be greater than zero")
+	}
+	if config.DrainDelay < 0 {
+		return errors.New("the drain delay must not be negative")
+	}
+	return nil
+}
+
+// ContentionWeight returns how much the measured CPU contention counts towards
+// the available CPU, given the config's cpu source.
+func (config Config) ContentionWeight() float64 {
+	switch config.CPUSource {
+	case measuredCPU:
+		return 1
+	case blendedCPU:
+		return config.CPUBlend
+	default:
+		return 0
+	}
+}
+
+// ThroughputModel returns the throughput model described by the config.
+func (config Config) ThroughputModel() (ThroughputModel, error) {
+	return NewThroughputModel(config.Model, config.Servers, config.QueueLength, config.Contention, config.Coherency)
+}
+
+// Sensitivity returns the sensitivity to each resource described by the config.
+func (config Config) Sensitivity() Sensitivity {
+	return Sensitivity{
+		CPU:             config.CPUSensitivity,
+		MemoryBandwidth: config.MemoryBandwidthSensitivity,
+		DiskIO:          config.DiskIOSensitivity,
+		Network:         config.NetworkSensitivity,
+	}
+}
+
+// String lists every setting in the config, for logging.
+func (config Config) String() string {
+	var lines = make([]string, 0, len(settings))
+	for _, s := range settings {
+		lines = append(lines, fmt.Sprintf("%s=%s", s.fileKey(), s.get(&config)))
+	}
+	return strings.Join(lines, " ")
+}
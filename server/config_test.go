@@ -0,0 +1,18 @@
+package main
+
+import "testing"
+
+func TestLoadConfigRejectsNonFiniteFloats(t *testing.T) {
+	var settings = []string{"cpu-blend", "cpu-sensitivity", "network-sensitivity", "contention", "coherency"}
+	for _, setting := range settings {
+		for _, value := range []string{"NaN", "Inf", "-Inf", "+inf"} {
+			var arg = "-" + setting + "=" + value
+			if _, err := LoadConfig([]string{arg}); err == nil {
+				t.Errorf("LoadConfig(%q) succeeded, want an error", arg)
+			}
+		}
+	}
+	if _, err := LoadConfig([]string{"-cpu-blend=0.25", "-cpu-sensitivity=1.5"}); err != nil {
+		t.Errorf("LoadConfig() with finite floats = %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"runtime"
+	"time"
+)
+
+// The sources the available CPU is taken from.
+const (
+	// simulatedCPU only counts the CPU stolen by simulated noisy neighbors.
+	simulatedCPU = "simulated"
+	// measuredCPU only counts the contention measured on the host.
+	measuredCPU = "measured"
+	// blendedCPU weighs the measured contention against the simulated neighbors.
+	blendedCPU = "blended"
+)
+
+const (
+	// defaultCPUBlend is the default weight of the measured CPU when blended.
+	defaultCPUBlend = 0.5
+	// contentionInterval is how often the host's CPU contention is measured.
+	contentionInterval = time.Second
+)
+
+// cpuCounters are the cumulative CPU counters read from the host at one time.
+// Contention is measured from the difference between two readings.
+type cpuCounters struct {
+	// total, steal and iowait are the host's CPU time from /proc/stat, in clock ticks.
+	total, steal, iowait uint64
+	// hasCgroup is true when the service's cgroup v2 CPU controller was found.
+	hasCgroup bool
+	// periods counts the cgroup's quota enforcement periods,
+	// and throttled the periods in which it used up its quota.
+	periods, throttled uint64
+	// quota is the cgroup's cpu.max limit, in CPUs. Zero means unlimited.
+	quota float64
+}
+
+// CPUContention is the contention for CPU measured on the host over an interval.
+// Every value but QuotaCPUs is a fraction from 0 to 1.
+type CPUContention struct {
+	// Steal is the share of the host's CPU time taken by the hypervisor for other guests.
+	Steal float64
+	// IOWait is the share of the host's CPU time spent idle, waiting on I/O.
+	IOWait float64
+	// Throttled is the share of the cgroup's enforcement periods in which it used
+	// up its quota. It's always 0 without a quota.
+	Throttled float64
+	// QuotaCPUs is the cgroup's CPU quota, in CPUs. Zero means unlimited.
+	QuotaCPUs float64
+	// Available is the share of CPU left to the service once the quota, and each kind
+	// of contention, are accounted for.
+	Available float64
+}
+
+// noContention is the contention assumed before there are two readings to compare.
+var noContention = CPUContention{Available: 1}
+
+// quotaShare returns the share of the CPUs visible to the service which its quota
+// lets it use, from 0 to 1. Without a quota, or with one over the visible CPUs, it's 1.
+func quotaShare(quota float64, cpus int) float64 {
+	if quota <= 0 || cpus <= 0 || quota >= float64(cpus) {
+		return 1
+	}
+	return quota / float64(cpus)
+}
+
+// contentionBetween returns the contention measured between two readings,
+// by a service which sees the given number of CPUs.
+func contentionBetween(before, after cpuCounters, cpus int) CPUContention {
+	var contention = CPUContention{QuotaCPUs: after.quota}
+	if total := delta(before.total, after.total); total > 0 {
+		contention.Steal = float64(delta(before.steal, after.steal)) / float64(total)
+		contention.IOWait = float64(delta(before.iowait, after.iowait)) / float64(total)
+	}
+	if periods := delta(before.periods, after.periods); periods > 0 {
+		contention.Throttled = float64(delta(before.throttled, after.throttled)) / float64(periods)
+	}
+	// Each kind of contention compounds the others, like the theft of each resource.
+	contention.Available = quotaShare(after.quota, cpus) * (1 - contention.Steal) * (1 - contention.IOWait) * (1 - contention.Throttled)
+	return contention
+}
+
+// delta returns how far a counter went up, or 0 if it was reset.
+func delta(before, after uint64) uint64 {
+	if after < before {
+		return 0
+	}
+	return after - before
+}
+
+// MeasureCPU starts measuring the CPU contention on the host every contentionInterval,
+// and counts what's left of the CPU towards AvailableCPU with the given weight,
+// from 0 to 1. It returns an error if the CPU can't be measured here.
+func (service *SimulatedService) MeasureCPU(weight float64) error {
+	var counters, err = readCPUCounters()
+	if err != nil {
+		return fmt.Errorf("measuring the CPU: %v", err)
+	}
+	if !counters.hasCgroup {
+		log.Printf("No cgroup v2 CPU controller found, so CPU throttling isn't measured")
+	}
+	service.updateState(func(state *State) error {
+		state.Contention = noContention
+		state.Contention.QuotaCPUs = counters.quota
+		state.Contention.Available = quotaShare(counters.quota, runtime.NumCPU())
+		state.ContentionWeight = weight
+		return nil
+	})
+	go service.measureContention(counters)
+	return nil
+}
+
+// measureContention compares each reading of the CPU counters with the one
+// before it, and keeps the contention in the state, until the service is
+// closed.
+func (service *SimulatedService) measureContention(last cpuCounters) {
+	var ticker = time.NewTicker(contentionInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-service.closed:
+			return
+		}
+		var counters, err = readCPUCounters()
+		if err != nil {
+			log.Printf("Error measuring the CPU, keeping the last measurement: %v", err)
+			continue
+		}
+		var contention = contentionBetween(last, counters, runtime.NumCPU())
+		last = counters
+		service.updateState(func(state *State) error {
+			state.Contention = contention
+			return nil
+		})
+	}
+}
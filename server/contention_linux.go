@@ -0,0 +1,182 @@
+//go:build linux
+// +build linux
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// The files the CPU counters are read from.
+const (
+	procStatPath   = "/proc/stat"
+	selfCgroupPath = "/proc/self/cgroup"
+	mountInfoPath  = "/proc/self/mountinfo"
+)
+
+// readCPUCounters reads the host's CPU counters from /proc/stat, and the
+// service's cgroup counters from cpu.stat and cpu.max under cgroup v2.
+// Without a cgroup v2 CPU controller, only /proc/stat is read.
+func readCPUCounters() (cpuCounters, error) {
+	var counters cpuCounters
+	var contents, err = ioutil.ReadFile(procStatPath)
+	if err != nil {
+		return counters, err
+	}
+	if err = parseProcStat(string(contents), &counters); err != nil {
+		return counters, err
+	}
+
+	var dir = cgroupDir()
+	if dir == "" {
+		return counters, nil
+	}
+	var stat, statErr = ioutil.ReadFile(filepath.Join(dir, "cpu.stat"))
+	var max, maxErr = ioutil.ReadFile(filepath.Join(dir, "cpu.max"))
+	// cpu.max is missing when the CPU controller isn't enabled for the cgroup.
+	if statErr != nil || maxErr != nil {
+		return counters, nil
+	}
+	if err = parseCPUStat(string(stat), &counters); err != nil {
+		return counters, err
+	}
+	if counters.quota, err = parseCPUMax(string(max)); err != nil {
+		return counters, err
+	}
+	counters.hasCgroup = true
+	return counters, nil
+}
+
+// parseProcStat reads the time spent in each state by every CPU
+// from the aggregate cpu line of /proc/stat:
+//
+//	cpu user nice system idle iowait irq softirq steal guest guest_nice
+//
+// Guest time is already counted in user and nice, so it's left out of the total.
+func parseProcStat(contents string, counters *cpuCounters) error {
+	for _, line := range strings.Split(contents, "\n") {
+		var fields = strings.Fields(line)
+		if len(fields) == 0 || fields[0] != "cpu" {
+			continue
+		}
+		if len(fields) < 9 {
+			return fmt.Errorf("expected at least 8 counters on the cpu line of %s, got %d", procStatPath, len(fields)-1)
+		}
+		var values [8]uint64
+		for i := range values {
+			var value, err = strconv.ParseUint(fields[i+1], 10, 64)
+			if err != nil {
+				return fmt.Errorf("parsing the cpu line of %s: %v", procStatPath, err)
+			}
+			values[i] = value
+			counters.total += value
+		}
+		counters.iowait, counters.steal = values[4], values[7]
+		return nil
+	}
+	return fmt.Errorf("no cpu line in %s", procStatPath)
+}
+
+// parseCPUStat reads the enforcement periods, and the throttled periods, from cpu.stat.
+// Both are missing, and left as zero, when the cgroup has no quota.
+func parseCPUStat(contents string, counters *cpuCounters) error {
+	for _, line := range strings.Split(contents, "\n") {
+		var fields = strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		var field *uint64
+		switch fields[0] {
+		case "nr_periods":
+			field = &counters.periods
+		case "nr_throttled":
+			field = &counters.throttled
+		default:
+			continue
+		}
+		var err error
+		if *field, err = strconv.ParseUint(fields[1], 10, 64); err != nil {
+			return fmt.Errorf("parsing %s in cpu.stat: %v", fields[0], err)
+		}
+	}
+	return nil
+}
+
+// parseCPUMax returns the quota in cpu.max, "$MAX $PERIOD", in CPUs.
+// A max of "max" means there's no quota, and returns 0.
+func parseCPUMax(contents string) (float64, error) {
+	var fields = strings.Fields(contents)
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("expected a quota and a period in cpu.max, got %q", strings.TrimSpace(contents))
+	}
+	if fields[0] == "max" {
+		return 0, nil
+	}
+	var quota, err = strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing the quota in cpu.max: %v", err)
+	}
+	var period uint64
+	if period, err = strconv.ParseUint(fields[1], 10, 64); err != nil || period == 0 {
+		return 0, errors.New("the period in cpu.max must be a positive number")
+	}
+	return float64(quota) / float64(period), nil
+}
+
+// cgroupDir returns the directory of the service's cgroup under the cgroup v2
+// hierarchy, or an empty string if the service isn't in one.
+func cgroupDir() string {
+	var cgroup, err = ioutil.ReadFile(selfCgroupPath)
+	if err != nil {
+		return ""
+	}
+	mountInfo, err := ioutil.ReadFile(mountInfoPath)
+	if err != nil {
+		return ""
+	}
+	return cgroupPath(string(cgroup), string(mountInfo))
+}
+
+// cgroupPath returns the directory of the cgroup v2 hierarchy named in
+// /proc/self/cgroup, where it's mounted according to /proc/self/mountinfo,
+// or an empty string if there's no such cgroup or it isn't mounted.
+func cgroupPath(cgroup, mountInfo string) string {
+	// The unified hierarchy is the line with hierarchy ID 0, e.g. "0::/nomad.slice/...".
+	var path string
+	for _, line := range strings.Split(cgroup, "\n") {
+		if strings.HasPrefix(line, "0::") {
+			path = strings.TrimPrefix(line, "0::")
+		}
+	}
+	if path == "" {
+		return ""
+	}
+	var root, mountPoint = cgroup2Mount(mountInfo)
+	if mountPoint == "" {
+		return ""
+	}
+	return filepath.Join(mountPoint, strings.TrimPrefix(path, root))
+}
+
+// cgroup2Mount returns the root of the cgroup v2 hierarchy which is mounted,
+// and where it's mounted, from /proc/self/mountinfo. On hybrid hosts,
+// it's mounted beside the cgroup v1 hierarchies, e.g. at /sys/fs/cgroup/unified.
+func cgroup2Mount(mountInfo string) (root, mountPoint string) {
+	// Each line is "ID parent major:minor root mount-point options ... - type source options".
+	for _, line := range strings.Split(mountInfo, "\n") {
+		var separator = strings.Index(line, " - ")
+		if separator < 0 {
+			continue
+		}
+		var fields, rest = strings.Fields(line[:separator]), strings.Fields(line[separator+3:])
+		if len(fields) >= 5 && len(rest) > 0 && rest[0] == "cgroup2" {
+			return fields[3], fields[4]
+		}
+	}
+	return "", ""
+}
@@ -0,0 +1,160 @@
+//go:build linux
+// +build linux
+
+package main
+
+import "testing"
+
+func TestParseProcStat(t *testing.T) {
+	var tests = []struct {
+		name     string
+		contents string
+		want     cpuCounters
+		wantErr  bool
+	}{
+		{
+			name: "kernel with guest counters",
+			contents: "cpu  4705 356 584 3699176 23060 0 277 1500 0 0\n" +
+				"cpu0 1393 280 290 924367 7012 0 154 500 0 0\n" +
+				"intr 1462898\n",
+			want: cpuCounters{total: 4705 + 356 + 584 + 3699176 + 23060 + 277 + 1500, iowait: 23060, steal: 1500},
+		},
+		{
+			name:     "kernel without guest counters",
+			contents: "cpu  10 20 30 40 50 60 70 80\n",
+			want:     cpuCounters{total: 360, iowait: 50, steal: 80},
+		},
+		{name: "too few counters", contents: "cpu  10 20 30 40 50\n", wantErr: true},
+		{name: "bad counter", contents: "cpu  10 20 30 x 50 60 70 80\n", wantErr: true},
+		{name: "no cpu line", contents: "cpu0 10 20 30 40 50 60 70 80\n", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got cpuCounters
+			var err = parseProcStat(test.contents, &got)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parseProcStat() error = %v, want an error: %v", err, test.wantErr)
+			}
+			if err == nil && got != test.want {
+				t.Errorf("parseProcStat() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseCPUStat(t *testing.T) {
+	var tests = []struct {
+		name     string
+		contents string
+		want     cpuCounters
+		wantErr  bool
+	}{
+		{
+			name: "with a quota",
+			contents: "usage_usec 8723000\nuser_usec 6000000\nsystem_usec 2723000\n" +
+				"nr_periods 1200\nnr_throttled 300\nthrottled_usec 4500000\n",
+			want: cpuCounters{periods: 1200, throttled: 300},
+		},
+		{
+			name:     "without a quota",
+			contents: "usage_usec 8723000\nuser_usec 6000000\nsystem_usec 2723000\n",
+			want:     cpuCounters{},
+		},
+		{name: "bad counter", contents: "nr_periods many\n", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got cpuCounters
+			var err = parseCPUStat(test.contents, &got)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("parseCPUStat() error = %v, want an error: %v", err, test.wantErr)
+			}
+			if err == nil && got != test.want {
+				t.Errorf("parseCPUStat() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseCPUMax(t *testing.T) {
+	var tests = []struct {
+		contents string
+		want     float64
+		wantErr  bool
+	}{
+		{contents: "max 100000\n", want: 0},
+		{contents: "200000 100000\n", want: 2},
+		{contents: "50000 100000\n", want: 0.5},
+		{contents: "50000\n", wantErr: true},
+		{contents: "half 100000\n", wantErr: true},
+		{contents: "50000 0\n", wantErr: true},
+	}
+	for _, test := range tests {
+		var got, err = parseCPUMax(test.contents)
+		if (err != nil) != test.wantErr {
+			t.Errorf("parseCPUMax(%q) error = %v, want an error: %v", test.contents, err, test.wantErr)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("parseCPUMax(%q) = %v, want %v", test.contents, got, test.want)
+		}
+	}
+}
+
+func TestCgroupPath(t *testing.T) {
+	const unified = "25 30 0:23 / /sys/fs/cgroup rw,nosuid,nodev,noexec,relatime shared:9 - cgroup2 cgroup2 rw\n"
+	const hybrid = "24 30 0:22 / /sys/fs/cgroup ro,nosuid,nodev,noexec shared:9 - tmpfs tmpfs ro,mode=755\n" +
+		"25 24 0:23 / /sys/fs/cgroup/unified rw,nosuid,nodev,noexec,relatime shared:10 - cgroup2 cgroup2 rw\n" +
+		"26 24 0:24 / /sys/fs/cgroup/cpu,cpuacct rw,nosuid,nodev,noexec,relatime shared:11 - cgroup cgroup rw,cpu,cpuacct\n"
+	// In a cgroup namespace, the hierarchy is mounted from the namespace's root.
+	const namespaced = "1200 1100 0:23 /nomad.slice /sys/fs/cgroup rw,nosuid,nodev,noexec,relatime - cgroup2 cgroup2 rw\n"
+	var tests = []struct {
+		name              string
+		cgroup, mountInfo string
+		want              string
+	}{
+		{
+			name:      "unified",
+			cgroup:    "0::/nomad.slice/alloc.scope\n",
+			mountInfo: unified,
+			want:      "/sys/fs/cgroup/nomad.slice/alloc.scope",
+		},
+		{
+			name:      "hybrid",
+			cgroup:    "12:cpu,cpuacct:/user.slice\n1:name=systemd:/user.slice/session-1.scope\n0::/user.slice/session-1.scope\n",
+			mountInfo: hybrid,
+			want:      "/sys/fs/cgroup/unified/user.slice/session-1.scope",
+		},
+		{
+			name:      "namespaced",
+			cgroup:    "0::/nomad.slice/alloc.scope\n",
+			mountInfo: namespaced,
+			want:      "/sys/fs/cgroup/alloc.scope",
+		},
+		{
+			name:      "root cgroup",
+			cgroup:    "0::/\n",
+			mountInfo: unified,
+			want:      "/sys/fs/cgroup",
+		},
+		{
+			name:      "cgroup v1 only",
+			cgroup:    "12:cpu,cpuacct:/user.slice\n",
+			mountInfo: hybrid,
+			want:      "",
+		},
+		{
+			name:      "cgroup v2 not mounted",
+			cgroup:    "0::/user.slice\n",
+			mountInfo: "26 24 0:24 / /sys/fs/cgroup/cpu rw - cgroup cgroup rw,cpu\n",
+			want:      "",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := cgroupPath(test.cgroup, test.mountInfo); got != test.want {
+				t.Errorf("cgroupPath() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
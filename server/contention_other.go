@@ -0,0 +1,11 @@
+//go:build !linux
+// +build !linux
+
+package main
+
+import "errors"
+
+// readCPUCounters fails everywhere but Linux, where the counters come from /proc and cgroups.
+func readCPUCounters() (cpuCounters, error) {
+	return cpuCounters{}, errors.New("the CPU can only be measured on Linux")
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestContentionBetween(t *testing.T) {
+	var tests = []struct {
+		name          string
+		before, after cpuCounters
+		cpus          int
+		want          CPUContention
+	}{
+		{
+			name:  "idle",
+			after: cpuCounters{total: 1000},
+			cpus:  4,
+			want:  CPUContention{Available: 1},
+		},
+		{
+			name:   "steal and iowait",
+			before: cpuCounters{total: 1000, steal: 10, iowait: 20},
+			after:  cpuCounters{total: 2000, steal: 110, iowait: 220},
+			cpus:   4,
+			want:   CPUContention{Steal: 0.1, IOWait: 0.2, Available: 0.9 * 0.8},
+		},
+		{
+			name:   "throttled",
+			before: cpuCounters{total: 1000, periods: 100, throttled: 10},
+			after:  cpuCounters{total: 2000, periods: 200, throttled: 60},
+			cpus:   4,
+			want:   CPUContention{Throttled: 0.5, Available: 0.5},
+		},
+		{
+			name:  "quota under the visible CPUs",
+			after: cpuCounters{total: 1000, quota: 1},
+			cpus:  4,
+			want:  CPUContention{QuotaCPUs: 1, Available: 0.25},
+		},
+		{
+			name:  "quota over the visible CPUs",
+			after: cpuCounters{total: 1000, quota: 8},
+			cpus:  4,
+			want:  CPUContention{QuotaCPUs: 8, Available: 1},
+		},
+		{
+			name:   "quota and steal",
+			before: cpuCounters{total: 1000},
+			after:  cpuCounters{total: 2000, steal: 500, quota: 2},
+			cpus:   4,
+			want:   CPUContention{Steal: 0.5, QuotaCPUs: 2, Available: 0.25},
+		},
+		{
+			name:   "reset counters",
+			before: cpuCounters{total: 5000, steal: 500},
+			after:  cpuCounters{total: 1000, steal: 100},
+			cpus:   4,
+			want:   CPUContention{Available: 1},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got = contentionBetween(test.before, test.after, test.cpus)
+			if !closeTo(got.Steal, test.want.Steal) || !closeTo(got.IOWait, test.want.IOWait) ||
+				!closeTo(got.Throttled, test.want.Throttled) || got.QuotaCPUs != test.want.QuotaCPUs ||
+				!closeTo(got.Available, test.want.Available) {
+				t.Errorf("contentionBetween() = %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
+)
+
+const (
+	// defaultEventHistory is how many events are kept when the config doesn't say.
+	defaultEventHistory = 1000
+	// defaultEventPage and maxEventPage are how many events GET /v1/events
+	// returns by default, and at most.
+	defaultEventPage = 100
+	maxEventPage     = 1000
+)
+
+// eventLog keeps the most recent events recorded by a SimulatedService,
+// forgetting the oldest once it's full.
+type eventLog struct {
+	sync.Mutex
+	// events holds the event with each ID at index (ID-1) % capacity.
+	events   []api.Event
+	capacity int
+	// lastID is the ID of the most recent event.
+	lastID uint64
+	// file, if set, is sent every event as a line of JSON.
+	file io.Writer
+}
+
+func newEventLog(capacity int) *eventLog {
+	return &eventLog{capacity: capacity}
+}
+
+// SetCapacity replaces the number of events kept, forgetting every event recorded so far.
+// It's meant to be called before the service starts serving.
+func (events *eventLog) SetCapacity(capacity int) {
+	events.Lock()
+	defer events.Unlock()
+	events.capacity = capacity
+	events.events = nil
+	events.lastID = 0
+}
+
+// SetFile sends every event recorded from now on to the writer, as a line of JSON.
+func (events *eventLog) SetFile(file io.Writer) {
+	events.Lock()
+	defer events.Unlock()
+	events.file = file
+}
+
+// Record gives the event the next ID, and the current time, and keeps it.
+func (events *eventLog) Record(event api.Event) {
+	events.Lock()
+	defer events.Unlock()
+	events.lastID++
+	event.ID = events.lastID
+	event.Time = time.Now()
+	if events.capacity > 0 {
+		if len(events.events) < events.capacity {
+			events.events = append(events.events, event)
+		} else {
+			events.events[(event.ID-1)%uint64(events.capacity)] = event
+		}
+	}
+	if events.file != nil {
+		var encoded, _ = json.Marshal(event)
+		if _, err := events.file.Write(append(encoded, '\n')); err != nil {
+			log.Printf("Error writing event %d to the event file: %v", event.ID, err)
+		}
+	}
+}
+
+// eventFilter selects events from the log.
+type eventFilter struct {
+	// types selects the events with any of the types. Empty selects every type.
+	types        map[string]bool
+	since, until time.Time
+	after        uint64
+	limit        int
+}
+
+// Query returns the oldest events which pass the filter, up to its limit.
+func (events *eventLog) Query(filter eventFilter) api.EventPage {
+	events.Lock()
+	defer events.Unlock()
+	var page = api.EventPage{Events: []api.Event{}}
+	var first = events.lastID - uint64(len(events.events)) + 1
+	if filter.after >= first {
+		first = filter.after + 1
+	}
+	for id := first; id <= events.lastID; id++ {
+		var event = events.events[(id-1)%uint64(events.capacity)]
+		if !filter.matches(event) {
+			continue
+		}
+		if len(page.Events) == filter.limit {
+			page.NextAfter = page.Events[len(page.Events)-1].ID
+			break
+		}
+		page.Events = append(page.Events, event)
+	}
+	return page
+}
+
+func (filter eventFilter) matches(event api.Event) bool {
+	if len(filter.types) > 0 && !filter.types[event.Type] {
+		return false
+	}
+	if !filter.since.IsZero() && event.Time.Before(filter.since) {
+		return false
+	}
+	return filter.until.IsZero() || !event.Time.After(filter.until)
+}
+
+// getEventFilter builds the filter from the request's URL parameters.
+func getEventFilter(req *http.Request) (eventFilter, *api.Error) {
+	var filter = eventFilter{limit: defaultEventPage}
+	if value := req.FormValue("type"); value != "" {
+		filter.types = make(map[string]bool)
+		for _, name := range strings.Split(value, ",") {
+			if !isEventType(name) {
+				return filter, invalidArgument("Unknown event type %q. Expected one of %v.", name, api.EventTypes)
+			}
+			filter.types[name] = true
+		}
+	}
+	var err error
+	if value := req.FormValue("since"); value != "" {
+		if filter.since, err = time.Parse(time.RFC3339Nano, value); err != nil {
+			return filter, invalidArgument("Error parsing since param: %v", err)
+		}
+	}
+	if value := req.FormValue("until"); value != "" {
+		if filter.until, err = time.Parse(time.RFC3339Nano, value); err != nil {
+			return filter, invalidArgument("Error parsing until param: %v", err)
+		}
+	}
+	if value := req.FormValue("after"); value != "" {
+		if filter.after, err = strconv.ParseUint(value, 10, 64); err != nil {
+			return filter, invalidArgument("Error parsing after param: %v", err)
+		}
+	}
+	if value := req.FormValue("limit"); value != "" {
+		var limit, err = strconv.ParseUint(value, 10, 64)
+		if err != nil || limit == 0 || limit > maxEventPage {
+			return filter, invalidArgument("The limit must be a number from 1 to %d.", maxEventPage)
+		}
+		filter.limit = int(limit)
+	}
+	return filter, nil
+}
+
+func isEventType(name string) bool {
+	for _, t := range api.EventTypes {
+		if name == t {
+			return true
+		}
+	}
+	return false
+}
+
+func (service *SimulatedService) handleV1Events(w http.ResponseWriter, req *http.Request) {
+	var filter, err = getEventFilter(req)
+	if err != nil {
+		writeV1Error(w, err)
+		return
+	}
+	writeV1(w, http.StatusOK, service.events.Query(filter))
+}
+
+// recordNeighbor records an event about the neighbor.
+func (service *SimulatedService) recordNeighbor(eventType string, neighbor Neighbor, message string) {
+	var v1 = neighbor.toV1()
+	service.events.Record(api.Event{Type: eventType, Message: message, Neighbor: &v1})
+}
+
+// recordLimits records an event with the limits of the state.
+func (service *SimulatedService) recordLimits(eventType string, state State, message string) {
+	var limits = api.Limits{
+		MaxThroughput:       state.MaxThroughput,
+		SoftLimit:           state.SoftLimit,
+		HardLimit:           state.HardLimit,
+		AvailableThroughput: state.AvailableThroughput(),
+		ModifiedSoftLimit:   state.ModifiedSoftLimit(),
+		ModifiedHardLimit:   state.ModifiedHardLimit(),
+	}
+	service.events.Record(api.Event{Type: eventType, Message: message, Limits: &limits})
+}
+
+// describeLimits formats the raw limits of the state for an event's message.
+func describeLimits(state State) string {
+	return fmt.Sprintf("max_throughput=%d soft_limit=%d hard_limit=%d", state.MaxThroughput, state.SoftLimit, state.HardLimit)
+}
+
+// recordLiveness records every time the service comes alive or dies,
+// whether from a change of load, limits or neighbors, until the service is closed.
+func (service *SimulatedService) recordLiveness() {
+	// The service starts alive, so only deaths and recoveries are recorded.
+	var alive = true
+	service.Watch(service.closed, func(view StateView) error {
+		if view.Alive == alive {
+			return nil
+		}
+		alive = view.Alive
+		var load = view.Load
+		if alive {
+			service.events.Record(api.Event{Type: api.EventAlive, Message: fmt.Sprintf("Alive again under a load of %d", load), Load: &load})
+		} else if view.Dying {
+			service.events.Record(api.Event{Type: api.EventDead, Message: "Dead after falling over from overload", Load: &load})
+		} else {
+			service.events.Record(api.Event{Type: api.EventDead, Message: fmt.Sprintf("Dead under a load of %d, past the hard limit of %d", load, view.HardLimit), Load: &load})
+		}
+		return nil
+	})
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
+)
+
+// ExplainThroughput returns the throughput of this service under the given load,
+// along with its inputs, the regime the load fell in, and the values behind it.
+// Every input comes from the same snapshot of the state.
+// A throughput drawn at random is the last one served under the same load,
+// capacity and model. If none was, it's a fresh sample from the sample
+// generator. Either way, explaining it doesn't change the throughputs served
+// by a seeded service.
+func (service *SimulatedService) ExplainThroughput(load uint64) api.Explanation {
+	return service.explainThroughput(load, service.explainServed)
+}
+
+// explainThroughput explains the throughput under the given load, asking
+// explainModel for the model's part of it.
+func (service *SimulatedService) explainThroughput(load uint64, explainModel func(state State, load uint64) Explanation) api.Explanation {
+	var state = service.Snapshot()
+	var dying = service.IsDying()
+	var explanation = api.Explanation{
+		Load:         load,
+		Stolen:       api.Resources(state.Stolen),
+		AvailableCPU: 100 * state.AvailableCPU(),
+		Availability: 100 * state.Availability(),
+		WarmUp:       100 * state.warmUp(),
+		Limits: api.Limits{
+			MaxThroughput:       state.MaxThroughput,
+			SoftLimit:           state.SoftLimit,
+			HardLimit:           state.HardLimit,
+			AvailableThroughput: state.AvailableThroughput(),
+			ModifiedSoftLimit:   state.ModifiedSoftLimit(),
+			ModifiedHardLimit:   state.ModifiedHardLimit(),
+		},
+		Dying: dying,
+	}
+	if state.ContentionWeight > 0 {
+		explanation.MeasuredCPU = &api.MeasuredCPU{
+			Available: 100 * state.Contention.Available,
+			Simulated: 100 * state.SimulatedCPU(),
+			Weight:    state.ContentionWeight,
+			Steal:     100 * state.Contention.Steal,
+			IOWait:    100 * state.Contention.IOWait,
+			Throttled: 100 * state.Contention.Throttled,
+			QuotaCPUs: state.Contention.QuotaCPUs,
+		}
+	}
+	// In real mode, the throughput is whatever the workers completed.
+	if state.Work != nil {
+		return withModel(explanation, state.Work.explain(time.Now()))
+	}
+	// Past the hard limit, the service has fallen over, and the model isn't consulted.
+	if dying || state.Overloaded(load) {
+		explanation.Model, explanation.Regime = modelName(state.Model), "dead"
+		explanation.Values = map[string]float64{}
+		return explanation
+	}
+	return withModel(explanation, explainModel(state, load))
+}
+
+// serveModel explains the throughput served under the given load, drawing
+// from the service's generator, and records any draw in the state.
+func (service *SimulatedService) serveModel(state State, load uint64) Explanation {
+	var capacity = state.Capacity()
+	var explanation = state.Model.Explain(load, capacity, service.rng)
+	if explanation.Draw != nil {
+		service.recordDraw(ServedDraw{Load: load, Capacity: capacity, Model: state.Model, Explanation: explanation})
+	}
+	return explanation
+}
+
+// sampleModel explains a sample of the throughput under the given load,
+// drawing from the sample generator.
+func (service *SimulatedService) sampleModel(state State, load uint64) Explanation {
+	return state.Model.Explain(load, state.Capacity(), service.sampleRNG)
+}
+
+// explainServed explains the throughput under the given load as it was last
+// served, if it was drawn at random, and as a sample otherwise.
+func (service *SimulatedService) explainServed(state State, load uint64) Explanation {
+	if served := state.LastDraw; served != nil && served.Load == load && served.Capacity == state.Capacity() && served.Model == state.Model {
+		return served.Explanation
+	}
+	return service.sampleModel(state, load)
+}
+
+// withModel fills in the explanation with what the model explained.
+func withModel(explanation api.Explanation, model Explanation) api.Explanation {
+	explanation.Model = model.Model
+	explanation.Regime = model.Regime
+	explanation.Throughput = model.Throughput
+	explanation.Values = model.Values
+	if explanation.Values == nil {
+		explanation.Values = map[string]float64{}
+	}
+	if model.Draw != nil {
+		explanation.Draw = &api.Draw{Min: model.Draw.Min, Max: model.Draw.Max, Value: model.Draw.Value}
+	}
+	return explanation
+}
+
+func (service *SimulatedService) handleV1Explain(w http.ResponseWriter, req *http.Request) {
+	var load, err = service.getV1Load(req)
+	if err != nil {
+		writeV1Error(w, err)
+		return
+	}
+	writeV1(w, http.StatusOK, service.ExplainThroughput(load))
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/rpc"
+)
+
+// grpcService serves the gRPC API of a SimulatedService.
+// It offers the same operations as the /v1 HTTP API, on the same state.
+type grpcService struct {
+	rpc.UnimplementedSimulationServer
+	service *SimulatedService
+}
+
+// NewGRPCServer returns a gRPC server for the service's gRPC API.
+func NewGRPCServer(service *SimulatedService) *grpc.Server {
+	var server = grpc.NewServer()
+	rpc.RegisterSimulationServer(server, &grpcService{service: service})
+	return server
+}
+
+// StopGRPC gives in-flight calls until the context is done to finish,
+// then cancels any which are still running, such as open streams.
+func StopGRPC(ctx context.Context, server *grpc.Server) {
+	var stopped = make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		server.Stop()
+	}
+}
+
+// load returns the requested load, or the load held by the service if none was requested.
+func (s *grpcService) load(requested *wrapperspb.UInt64Value) uint64 {
+	if requested != nil {
+		return requested.GetValue()
+	}
+	var load, _ = s.service.load.Load(time.Now())
+	return load
+}
+
+func (s *grpcService) Health(ctx context.Context, req *rpc.HealthRequest) (*rpc.HealthResponse, error) {
+	var load = s.load(req.GetLoad())
+	var state = s.service.Snapshot()
+	return &rpc.HealthResponse{
+		Alive:        s.service.IsAlive(load),
+		Load:         load,
+		AvailableCpu: 100 * state.AvailableCPU(),
+		Stolen:       resourcesToProto(state.Stolen),
+	}, nil
+}
+
+func (s *grpcService) Throughput(ctx context.Context, req *rpc.ThroughputRequest) (*rpc.ThroughputResponse, error) {
+	var load = s.load(req.GetLoad())
+	return &rpc.ThroughputResponse{Load: load, Throughput: s.service.CalculateThroughput(load)}, nil
+}
+
+func (s *grpcService) GetLimits(ctx context.Context, req *rpc.GetLimitsRequest) (*rpc.Limits, error) {
+	return limitsToProto(s.service.Snapshot()), nil
+}
+
+func (s *grpcService) UpdateLimits(ctx context.Context, req *rpc.UpdateLimitsRequest) (*rpc.Limits, error) {
+	var update LimitsUpdate
+	if req.GetMaxThroughput() != nil {
+		var throughput = req.GetMaxThroughput().GetValue()
+		update.MaxThroughput = &throughput
+	}
+	if req.GetSoftLimit() != nil {
+		var soft = req.GetSoftLimit().GetValue()
+		update.SoftLimit = &soft
+	}
+	if req.GetHardLimit() != nil {
+		var hard = req.GetHardLimit().GetValue()
+		update.HardLimit = &hard
+	}
+	if _, err := s.service.UpdateLimits(update); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return limitsToProto(s.service.Snapshot()), nil
+}
+
+func (s *grpcService) AddNeighbor(ctx context.Context, req *rpc.AddNeighborRequest) (*rpc.AddNeighborResponse, error) {
+	var resources = resourcesFromProto(req.GetResources())
+	if resources.IsZero() {
+		return nil, status.Errorf(codes.InvalidArgument, "expected at least one of %v", AllResources)
+	}
+	var qos, err = qosFromProto(req.GetQos())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	ttl, err := leaseTTL(req.GetTtlSeconds())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	var address string
+	if p, ok := peer.FromContext(ctx); ok {
+		address = p.Addr.String()
+	}
+	neighbor, previous, err := s.service.AddNeighbor(Neighbor{
+		Address:   address,
+		Resources: resources,
+		Label:     req.GetLabel(),
+		JobID:     req.GetJobId(),
+		QoS:       qos,
+	}, ttl)
+	if rejected, ok := err.(*AdmissionError); ok {
+		s.service.metrics.ObserveNeighborCall("reject")
+		return nil, status.Error(codes.ResourceExhausted, rejected.Error())
+	} else if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	s.service.metrics.ObserveNeighborCall("add")
+	return &rpc.AddNeighborResponse{
+		Neighbor:       neighborToProto(neighbor),
+		PreviousStolen: resourcesToProto(previous),
+		Stolen:         resourcesToProto(s.service.Stolen()),
+	}, nil
+}
+
+func (s *grpcService) RenewNeighbor(ctx context.Context, req *rpc.RenewNeighborRequest) (*rpc.Neighbor, error) {
+	ttl, err := leaseTTL(req.GetTtlSeconds())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	var neighbor, ok = s.service.RenewNeighbor(req.GetId(), ttl)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no neighbor with ID %q", req.GetId())
+	}
+	s.service.metrics.ObserveNeighborCall("renew")
+	return neighborToProto(neighbor), nil
+}
+
+func (s *grpcService) RemoveNeighbor(ctx context.Context, req *rpc.RemoveNeighborRequest) (*rpc.RemoveNeighborResponse, error) {
+	var neighbor, ok = s.service.RemoveNeighbor(req.GetId())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no neighbor with ID %q", req.GetId())
+	}
+	s.service.metrics.ObserveNeighborCall("remove")
+	return &rpc.RemoveNeighborResponse{
+		Neighbor: neighborToProto(neighbor),
+		Stolen:   resourcesToProto(s.service.Stolen()),
+	}, nil
+}
+
+func (s *grpcService) ListNeighbors(ctx context.Context, req *rpc.ListNeighborsRequest) (*rpc.ListNeighborsResponse, error) {
+	var neighbors = s.service.Neighbors()
+	var response = &rpc.ListNeighborsResponse{
+		Neighbors: make([]*rpc.Neighbor, 0, len(neighbors)),
+		Stolen:    resourcesToProto(s.service.Stolen()),
+	}
+	for _, neighbor := range neighbors {
+		response.Neighbors = append(response.Neighbors, neighborToProto(neighbor))
+	}
+	return response, nil
+}
+
+func (s *grpcService) WatchState(req *rpc.WatchStateRequest, stream rpc.Simulation_WatchStateServer) error {
+	return s.service.Watch(stream.Context().Done(), func(view StateView) error {
+		return stream.Send(stateToProto(view, time.Now()))
+	})
+}
+
+func resourcesToProto(resources Resources) *rpc.Resources {
+	return &rpc.Resources{
+		Cpu:             resources.CPU,
+		MemoryBandwidth: resources.MemoryBandwidth,
+		DiskIo:          resources.DiskIO,
+		Network:         resources.Network,
+	}
+}
+
+func resourcesFromProto(resources *rpc.Resources) Resources {
+	return Resources{
+		CPU:             resources.GetCpu(),
+		MemoryBandwidth: resources.GetMemoryBandwidth(),
+		DiskIO:          resources.GetDiskIo(),
+		Network:         resources.GetNetwork(),
+	}
+}
+
+// qosToProto and qosFromProto map QoS classes to the enum in the gRPC API.
+var qosToProto = map[QoS]rpc.QoS{
+	QoSGuaranteed: rpc.QoS_QOS_GUARANTEED,
+	QoSBurstable:  rpc.QoS_QOS_BURSTABLE,
+	QoSBestEffort: rpc.QoS_QOS_BEST_EFFORT,
+}
+
+func qosFromProto(qos rpc.QoS) (QoS, error) {
+	if qos == rpc.QoS_QOS_UNSPECIFIED {
+		return QoSGuaranteed, nil
+	}
+	for class, value := range qosToProto {
+		if value == qos {
+			return class, nil
+		}
+	}
+	return "", fmt.Errorf("unknown QoS class %v", qos)
+}
+
+func neighborToProto(neighbor Neighbor) *rpc.Neighbor {
+	return &rpc.Neighbor{
+		Id:        neighbor.ID,
+		Address:   neighbor.Address,
+		Resources: resourcesToProto(neighbor.Resources),
+		StartedAt: timestamppb.New(neighbor.StartedAt),
+		ExpiresAt: timestamppb.New(neighbor.ExpiresAt),
+		Label:     neighbor.Label,
+		JobId:     neighbor.JobID,
+		Qos:       qosToProto[neighbor.QoS],
+	}
+}
+
+func limitsToProto(state State) *rpc.Limits {
+	return &rpc.Limits{
+		MaxThroughput:       state.MaxThroughput,
+		SoftLimit:           state.SoftLimit,
+		HardLimit:           state.HardLimit,
+		AvailableThroughput: state.AvailableThroughput(),
+		ModifiedSoftLimit:   state.ModifiedSoftLimit(),
+		ModifiedHardLimit:   state.ModifiedHardLimit(),
+	}
+}
+
+func stateToProto(view StateView, now time.Time) *rpc.State {
+	return &rpc.State{
+		Time:                timestamppb.New(now),
+		MaxThroughput:       view.MaxThroughput,
+		SoftLimit:           view.SoftLimit,
+		HardLimit:           view.HardLimit,
+		AvailableThroughput: view.AvailableThroughput,
+		ModifiedSoftLimit:   view.ModifiedSoftLimit,
+		ModifiedHardLimit:   view.ModifiedHardLimit,
+		Stolen:              resourcesToProto(view.Stolen),
+		Neighbors:           uint32(view.Neighbors),
+		Load:                view.Load,
+		LoadSource:          view.LoadSource,
+		Alive:               view.Alive,
+		Ready:               view.Ready,
+		Dying:               view.Dying,
+		Draining:            view.Draining,
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
+)
+
+// sampleInterval is how often the service samples itself for its history.
+const sampleInterval = time.Second
+
+// A resolution is the span averaged into each point of a series,
+// and how many points of the series are kept.
+type resolution struct {
+	name   string
+	span   time.Duration
+	points int
+}
+
+// resolutions lists the series kept in the history, finest first:
+// ten minutes by the second, two hours by ten seconds, and a day by the minute.
+var resolutions = []resolution{
+	{name: "1s", span: time.Second, points: 600},
+	{name: "10s", span: 10 * time.Second, points: 720},
+	{name: "1m", span: time.Minute, points: 1440},
+}
+
+// series holds the most recent points at one resolution.
+type series struct {
+	resolution
+	// points holds the completed points, oldest at start once it's full.
+	points []api.HistoryPoint
+	start  int
+	// pending sums the samples of the point still filling up.
+	pending api.HistoryPoint
+}
+
+// add averages the sample into the point covering its time,
+// completing the pending point if the sample falls after it.
+func (s *series) add(sample api.HistoryPoint) {
+	var bucket = sample.Time.Truncate(s.span)
+	if s.pending.Samples > 0 && !bucket.Equal(s.pending.Time) {
+		s.complete(s.average(s.pending))
+		s.pending = api.HistoryPoint{}
+	}
+	s.pending.Time = bucket
+	s.pending.Throughput += sample.Throughput
+	s.pending.AvailableCPU += sample.AvailableCPU
+	s.pending.Load += sample.Load
+	s.pending.Samples++
+}
+
+func (s *series) complete(point api.HistoryPoint) {
+	if len(s.points) < s.resolution.points {
+		s.points = append(s.points, point)
+		return
+	}
+	s.points[s.start] = point
+	s.start = (s.start + 1) % len(s.points)
+}
+
+// average turns the sums of a pending point into averages.
+func (s *series) average(point api.HistoryPoint) api.HistoryPoint {
+	var n = float64(point.Samples)
+	point.Throughput /= n
+	point.AvailableCPU /= n
+	point.Load /= n
+	return point
+}
+
+// between returns the points, including the pending point, which start within the times.
+func (s *series) between(since, until time.Time) []api.HistoryPoint {
+	var list = make([]api.HistoryPoint, 0, len(s.points)+1)
+	var within = func(point api.HistoryPoint) bool {
+		return (since.IsZero() || !point.Time.Before(since)) && (until.IsZero() || !point.Time.After(until))
+	}
+	for i := range s.points {
+		var point = s.points[(s.start+i)%len(s.points)]
+		if within(point) {
+			list = append(list, point)
+		}
+	}
+	if s.pending.Samples > 0 && within(s.pending) {
+		list = append(list, s.average(s.pending))
+	}
+	return list
+}
+
+// History keeps samples of a SimulatedService at several resolutions.
+type History struct {
+	sync.Mutex
+	series []*series
+}
+
+// NewHistory is the constructor for History.
+func NewHistory() *History {
+	var history = &History{}
+	for _, r := range resolutions {
+		history.series = append(history.series, &series{resolution: r})
+	}
+	return history
+}
+
+// Record adds the sample to every series.
+func (history *History) Record(sample api.HistoryPoint) {
+	history.Lock()
+	defer history.Unlock()
+	for _, s := range history.series {
+		s.add(sample)
+	}
+}
+
+// Query returns the points of the series with the given span which start within the times.
+// It returns false if no series has that span.
+func (history *History) Query(span time.Duration, since, until time.Time) (api.History, bool) {
+	history.Lock()
+	defer history.Unlock()
+	for _, s := range history.series {
+		if s.span == span {
+			return api.History{Resolution: s.name, Points: s.between(since, until)}, true
+		}
+	}
+	return api.History{}, false
+}
+
+// sampleHistory samples the service every sampleInterval until the service is closed.
+func (service *SimulatedService) sampleHistory() {
+	var ticker = time.NewTicker(sampleInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			service.recordSample(now)
+		case <-service.closed:
+			return
+		}
+	}
+}
+
+// recordSample samples the throughput, available CPU and load of the service into its history.
+func (service *SimulatedService) recordSample(now time.Time) {
+	var sample = service.sampleThroughput(now)
+	service.history.Record(api.HistoryPoint{
+		Time:         now,
+		Throughput:   float64(sample.Throughput),
+		AvailableCPU: sample.AvailableCPU,
+		Load:         float64(sample.Load),
+		Samples:      1,
+	})
+}
+
+func (service *SimulatedService) handleV1History(w http.ResponseWriter, req *http.Request) {
+	var span = resolutions[0].span
+	var err error
+	if value := req.FormValue("resolution"); value != "" {
+		if span, err = time.ParseDuration(value); err != nil {
+			writeV1Error(w, invalidArgument("Error parsing resolution param: %v", err))
+			return
+		}
+	}
+	var since, until time.Time
+	if value := req.FormValue("since"); value != "" {
+		if since, err = time.Parse(time.RFC3339Nano, value); err != nil {
+			writeV1Error(w, invalidArgument("Error parsing since param: %v", err))
+			return
+		}
+	}
+	if value := req.FormValue("until"); value != "" {
+		if until, err = time.Parse(time.RFC3339Nano, value); err != nil {
+			writeV1Error(w, invalidArgument("Error parsing until param: %v", err))
+			return
+		}
+	}
+	var history, ok = service.history.Query(span, since, until)
+	if !ok {
+		var names = make([]string, 0, len(resolutions))
+		for _, r := range resolutions {
+			names = append(names, r.name)
+		}
+		writeV1Error(w, invalidArgument("Unknown resolution %v. Expected one of %v.", span, names))
+		return
+	}
+	writeV1(w, http.StatusOK, history)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// requestTimeout is the default bound on how long a request can take.
+// Simulated latencies are capped at the service's timeout, since clients give up after that.
+const requestTimeout = 10 * time.Second
+
+// minSlack keeps queueing delay finite as utilization approaches 100%.
+const minSlack = 0.01
+
+// LatencyPercentile returns the simulated latency of the given quantile
+// (e.g. 0.99 for p99) under the given load.
+//
+// While the load is below the available throughput, the service behaves
+// like an M/M/1 queue, whose response time is exponentially distributed
+// with rate μ(1-ρ). Since noisy neighbors shrink μ, they push up utilization,
+// and with it tail latency, well before throughput is affected.
+// Past the available throughput, excess requests wait in a backlog which
+// grows by the excess every second. Past the hard limit, or while the service
+// is dying, requests time out.
+func (service *SimulatedService) LatencyPercentile(load uint64, quantile float64) time.Duration {
+	var state = service.Snapshot()
+	if service.IsDying() || state.Overloaded(load) {
+		return service.Timeout
+	}
+	var available = float64(state.AvailableThroughput())
+	if available == 0 {
+		return service.Timeout
+	}
+	var utilization = float64(load) / available
+	var slack = math.Max(1-utilization, minSlack)
+	var seconds = -math.Log(1-quantile) / (available * slack)
+	// Add the time spent waiting behind the backlog.
+	if utilization > 1 {
+		seconds += utilization - 1
+	}
+	var latency = time.Duration(seconds * float64(time.Second))
+	if latency > service.Timeout {
+		return service.Timeout
+	}
+	return latency
+}
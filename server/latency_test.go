@@ -0,0 +1,21 @@
+package main
+
+import (
+	"sync/atomic"
+	"testing"
+)
+
+func TestLatencyPercentileTimesOutWhenDead(t *testing.T) {
+	var service = NewSimulatedService(1000, 1500, 2000)
+	defer service.Close()
+	if got := service.LatencyPercentile(10, 0.5); got >= service.Timeout {
+		t.Fatalf("p50 under light load = %v, want under the timeout of %v", got, service.Timeout)
+	}
+	if got := service.LatencyPercentile(2500, 0.5); got != service.Timeout {
+		t.Errorf("p50 past the hard limit = %v, want the timeout of %v", got, service.Timeout)
+	}
+	atomic.StoreInt32(&service.dying, 1)
+	if got := service.LatencyPercentile(10, 0.5); got != service.Timeout {
+		t.Errorf("p50 under light load while dying = %v, want the timeout of %v", got, service.Timeout)
+	}
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// loadWindow is the span over which requests are counted to measure load.
+	loadWindow = time.Second
+	// loadBuckets is the number of slices the window is divided into.
+	loadBuckets = 10
+)
+
+// LoadTracker holds the current load on a SimulatedService.
+// The load is either set explicitly, e.g. by a load balancer or the client,
+// or measured from the requests received over a rolling one-second window.
+type LoadTracker struct {
+	sync.Mutex
+	// explicit is the load set by a caller, if hasExplicit is true.
+	explicit    uint64
+	hasExplicit bool
+	// buckets count the requests received in each slice of the window.
+	// The bucket for the current slice is at index (current % loadBuckets).
+	buckets [loadBuckets]uint64
+	current int64
+}
+
+// NewLoadTracker is the constructor for a LoadTracker.
+func NewLoadTracker() *LoadTracker {
+	return &LoadTracker{}
+}
+
+// Set fixes the load at the given value, overriding the measured load.
+func (tracker *LoadTracker) Set(load uint64) {
+	tracker.Lock()
+	defer tracker.Unlock()
+	tracker.explicit = load
+	tracker.hasExplicit = true
+}
+
+// Clear forgets the explicitly set load, so the measured load is used instead.
+func (tracker *LoadTracker) Clear() {
+	tracker.Lock()
+	defer tracker.Unlock()
+	tracker.hasExplicit = false
+}
+
+// Observe counts a request received at the given time.
+func (tracker *LoadTracker) Observe(now time.Time) {
+	tracker.Lock()
+	defer tracker.Unlock()
+	tracker.advance(now)
+	tracker.buckets[tracker.current%loadBuckets]++
+}
+
+// Load returns the current load, and whether it was set explicitly.
+func (tracker *LoadTracker) Load(now time.Time) (load uint64, explicit bool) {
+	tracker.Lock()
+	defer tracker.Unlock()
+	if tracker.hasExplicit {
+		return tracker.explicit, true
+	}
+	tracker.advance(now)
+	for _, count := range tracker.buckets {
+		load += count
+	}
+	return load, false
+}
+
+// advance moves the window forward to the given time,
+// emptying the buckets of any slices which have fallen out of it.
+// The caller must hold the tracker's lock.
+func (tracker *LoadTracker) advance(now time.Time) {
+	var slice = now.UnixNano() / int64(loadWindow/loadBuckets)
+	if slice-tracker.current >= loadBuckets {
+		tracker.buckets = [loadBuckets]uint64{}
+	} else {
+		for i := tracker.current + 1; i <= slice; i++ {
+			tracker.buckets[i%loadBuckets] = 0
+		}
+	}
+	if slice > tracker.current {
+		tracker.current = slice
+	}
+}
@@ -2,17 +2,25 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html"
-	"math"
+	"log"
 	"math/rand"
+	"net"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
-	"sync/atomic"
+	"sync"
 	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
 )
 
+// The starting limits are the defaults of the corresponding config settings.
 const (
 	startingThroughput = 1000
 	startingSoft       = 1500
@@ -20,17 +28,80 @@ const (
 )
 
 func main() {
-	var service = NewSimulatedService(startingThroughput, startingSoft, startingHard)
-	var port = ":8080"
+	var config, err = LoadConfig(os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("Starting with config: %v", config)
+
+	// The model can't fail to build, since it's checked when the config is loaded.
+	var model, _ = config.ThroughputModel()
+	var service = NewSimulatedService(config.MaxThroughput, config.SoftLimit, config.HardLimit)
+	service.SetModel(model)
+	// Nor can the sensitivity be invalid, for the same reason.
+	service.SetSensitivity(config.Sensitivity())
+	service.Seed(config.Seed)
+	service.StrictHealth = config.StrictHealth
+	service.WarmUp = config.WarmUp
+	service.Timeout = config.WriteTimeout
+	service.Admission = AdmissionPolicy{
+		MaxStolen:           config.MaxStolen,
+		BurstableMaxStolen:  config.BurstableMaxStolen,
+		BestEffortMaxStolen: config.BestEffortMaxStolen,
+	}
+	if config.CPUSource != simulatedCPU {
+		if err := service.MeasureCPU(config.ContentionWeight()); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if config.Mode == realMode {
+		service.SetWork(NewWorkPool(config.Workers, config.WorkQueue, config.WorkCPU))
+	}
+	service.events.SetCapacity(int(config.EventHistory))
+	if config.EventFile != "" {
+		var file, err = os.OpenFile(config.EventFile, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		service.events.SetFile(file)
+	}
+	if config.CrashAfter > 0 {
+		var policy = OverloadPolicy{CrashAfter: config.CrashAfter, DeathSpiral: config.DeathSpiral}
+		go service.WatchOverload(policy, os.Exit)
+	}
 	var server = &http.Server{
-		Addr:           port,
+		Addr:           config.Listen,
 		Handler:        service,
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
+		ReadTimeout:    config.ReadTimeout,
+		WriteTimeout:   config.WriteTimeout,
 		MaxHeaderBytes: 1 << 20,
 	}
-	fmt.Printf("Listening on port %v\n", port)
-	server.ListenAndServe()
+	// The gRPC API shares the service's state, but listens on its own address.
+	var grpcServer *grpc.Server
+	if config.GRPCListen != "" {
+		var listener, err = net.Listen("tcp", config.GRPCListen)
+		if err != nil {
+			log.Fatal(err)
+		}
+		grpcServer = NewGRPCServer(service)
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatal(err)
+			}
+		}()
+		fmt.Printf("Serving the gRPC API on %v\n", config.GRPCListen)
+	}
+	go ReloadOnHangup(service, config, os.Args[1:])
+	if config.Mode == realMode {
+		fmt.Printf("Listening on %v, measuring the throughput of /v1/work\n", config.Listen)
+	} else {
+		fmt.Printf("Listening on %v with the %v throughput model\n", config.Listen, config.Model)
+	}
+	err = Serve(server, grpcServer, service, config.DrainDelay, config.ShutdownTimeout)
+	if err != nil && err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
 }
 
 // A SimulatedService is an HTTP server which simulates HTTP traffic.
@@ -38,49 +109,192 @@ func main() {
 // 1. Respond to liveliness checks.
 // 2. Provive a simulated throughput metric.
 // 3. Modify its throughput with requests from CPU-stealing noisy neighbors.
+// 4. On Linux, count the CPU contention measured from cgroups and /proc/stat, with -cpu-source.
+// Each noisy neighbor holds a lease on the CPU it steals, which it must renew
+// before the lease's TTL runs out. Expired leases are reaped automatically.
 // HTTP API:
+// The load used by the endpoints below defaults to the load held by the server,
+// but can be overridden with the load URL parameter.
+// Under /v1, bodies are JSON, and every error is returned in the same envelope
+// with a matching status code. The types are defined in the api package.
+// GET  /v1/health -> return the server status, with 503 when dead.
+// GET  /v1/ready -> return whether the server should receive more traffic, with 503 when not.
+// GET  /v1/load -> return the current load, and whether it was set or measured.
+// POST /v1/load -> set the current load.
+// DELETE /v1/load -> go back to measuring the load.
+// GET  /v1/throughput -> return the number of requests handled in the last second.
+// GET  /v1/throughput/explain -> explain the throughput: its inputs, regime and intermediate values.
+// GET  /v1/latency -> return the latency percentiles.
+// GET|POST /v1/work -> in real mode, burn a request's worth of CPU on the worker pool, or 503 when the queue is full.
+// GET  /v1/limits -> return the max throughput, soft limit and hard limit, raw and modified.
+// POST /v1/limits -> edit the max throughput, soft limit, or hard limit.
+// GET  /v1/neighbors -> list every noisy neighbor.
+// POST /v1/neighbors -> steal resources, returning a lease with 201, or 409 if the steal isn't admitted.
+// GET  /v1/neighbors/{id} -> describe the noisy neighbor with the given ID.
+// DELETE /v1/neighbors/{id} -> release the lease with the given ID.
+// POST /v1/neighbors/{id}/renew -> extend the lease with the given ID.
+// GET  /v1/events -> page through the history of state changes, filtered by type and time.
+// GET  /v1/history -> return the throughput, available CPU and load sampled over time, at 1s, 10s or 1m resolution.
+// GET  /v1/stream -> stream Server-Sent Events: the state whenever it changes, and throughput samples.
+// GET  /metrics -> return the state of the server in the Prometheus text format
+// GET  /openapi.json -> return the OpenAPI document describing every route.
+// gRPC API:
+// When -grpc-listen is set, the same operations, plus a stream of state changes,
+// are served over gRPC on that address. See rpc/simulation.proto.
+// The routes below predate /v1. They still work, but are deprecated: their
+// responses carry a Deprecation header, and a Link to the /v1 route replacing them.
 // GET  /healthz -> return the server status.
+// GET  /readyz -> return whether the server should receive more traffic.
 // GET  /metrics/throughput -> return the number of requests handled in the last second
-// POST /server-state -> edit the max throughput, soft limit, or hard limit.
+// GET  /metrics/latency -> return the latency percentiles at the given load
+// POST /metrics/throughput -> edit the max throughput, soft limit, or hard limit.
+// GET  /load -> return the current load, and whether it was set or measured.
+// POST /load -> set the current load, overriding the measured load.
+// DELETE /load -> go back to measuring the load from requests received in the last second.
+// GET  /neighbors/add -> steal CPU, returning a lease ID, or 409 if the steal isn't admitted.
+// GET  /neighbors/renew -> extend the lease with the given ID.
+// GET  /neighbors/remove -> release the lease with the given ID.
+// GET  /neighbors -> list every noisy neighbor.
+// GET  /neighbors/{id} -> describe the noisy neighbor with the given ID.
+// DELETE /neighbors/{id} -> remove the noisy neighbor with the given ID.
 type SimulatedService struct {
-	MaxThroughput,
-	RequestSoftLimit,
-	RequestHardLimit uint64
+	// stateLock guards the state, since it's changed by neighbors and
+	// POST /metrics/throughput while other requests read it.
+	// Read it through Snapshot, and change it through updateState.
+	stateLock sync.RWMutex
+	state     State
 
-	StolenCPU uint64
+	// StrictHealth makes /healthz respond with 503 when the service is dead,
+	// and 400 for bad input, instead of always responding with 200.
+	StrictHealth bool
+	// WarmUp is how long the service takes to reach full capacity after starting.
+	WarmUp time.Duration
+	// Timeout is how long clients wait for a response before giving up.
+	Timeout time.Duration
+	// Admission caps how much CPU noisy neighbors may steal.
+	Admission AdmissionPolicy
+
+	startedAt time.Time
+	// rng is the source of all randomness in the simulation.
+	rng *rand.Rand
+	// sampleRNG is drawn from by the history, the streams and the explanations,
+	// which sample the throughput rather than serve it, so they never shift
+	// the draws of rng. Explanations only draw when no throughput was served.
+	sampleRNG *rand.Rand
+	// dying is set to 1 once the service has been overloaded for too long.
+	dying int32
+	// draining is set to 1 once the service has started shutting down.
+	draining int32
+
+	neighbors *neighborRegistry
+	metrics   *Metrics
+	load      *LoadTracker
+	events    *eventLog
+	history   *History
+	// changes wakes watchers whenever the state, load or liveness changes.
+	changes changeNotifier
+	// streamsEnded is closed, once, to end every stream on shutdown.
+	streamsEnded chan struct{}
+	endStreams   sync.Once
+	// closed is closed, once, to stop the service's background goroutines.
+	closed    chan struct{}
+	closeOnce sync.Once
 }
 
 // NewSNewSimulatedService is the constructor for a SimulatedService.
+// It starts goroutines which reap expired neighbors, and record liveness and
+// history, until the service is closed.
 func NewSimulatedService(maxThroughput, softLimit, hardLimit uint64) *SimulatedService {
-	return &SimulatedService{
-		MaxThroughput:    maxThroughput,
-		RequestSoftLimit: softLimit,
-		RequestHardLimit: hardLimit,
+	var service = &SimulatedService{
+		state: State{
+			MaxThroughput: maxThroughput,
+			SoftLimit:     softLimit,
+			HardLimit:     hardLimit,
+			Model:         StepModel{},
+			Sensitivity:   DefaultSensitivity(),
+		},
+		neighbors: newNeighborRegistry(),
+		metrics:   NewMetrics(),
+		load:      NewLoadTracker(),
+		events:    newEventLog(defaultEventHistory),
+		history:   NewHistory(),
+		Timeout:   requestTimeout,
+		Admission: DefaultAdmissionPolicy(),
+		startedAt: time.Now(),
+		rng:       NewRand(time.Now().UnixNano()),
+		sampleRNG: NewRand(time.Now().UnixNano()),
+
+		streamsEnded: make(chan struct{}),
+		closed:       make(chan struct{}),
 	}
+	go service.reapNeighbors()
+	go service.recordLiveness()
+	go service.sampleHistory()
+	return service
 }
 
 // ServServeHTTP fulfills the http.Handler interface.
 func (service *SimulatedService) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var path = html.EscapeString(req.URL.Path)
+	var route, handler = service.route(path)
+	// Every request counts towards the measured load.
+	service.load.Observe(time.Now())
+	// Record the status of every response for the Prometheus metrics.
+	var recorder = &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	if successor, ok := legacySuccessors[route]; ok {
+		markDeprecated(recorder, successor)
+	}
+	handler(recorder, req)
+	service.metrics.ObserveRequest(route, recorder.status)
+}
+
+// route returns the name of the route matching the path, and its handler.
+// Route names are used as metric labels, so they're free of IDs.
+func (service *SimulatedService) route(path string) (string, http.HandlerFunc) {
 	// Switch on the URL:
 	// Forward the handler func for each URL
-	var path = html.EscapeString(req.URL.Path)
 	switch {
+	case strings.HasPrefix(path, api.Version+"/"):
+		return service.routeV1(path)
 	case strings.HasPrefix(path, "/healthz"):
-		service.handleHealthCheck(w, req)
+		return "/healthz", service.handleHealthCheck
+	case strings.HasPrefix(path, "/readyz"):
+		return "/readyz", service.handleReadiness
+	case path == "/load":
+		return "/load", service.handleLoad
+	case path == "/metrics":
+		return "/metrics", service.handlePrometheus
+	case path == openAPIPath:
+		return openAPIPath, service.handleOpenAPI
 	case strings.HasPrefix(path, "/metrics/throughput"):
-		service.handleThroughput(w, req)
-	case strings.HasPrefix(path, "/neighbors/add"):
-		service.handleNeighborsAdd(w, req)
-	case strings.HasPrefix(path, "/neighbors/remove"):
-		service.handleNeighborsRemove(w, req)
+		return "/metrics/throughput", service.handleThroughput
+	case strings.HasPrefix(path, "/metrics/latency"):
+		return "/metrics/latency", service.handleLatency
+	// Neighbor IDs are hex, so they can start with "add": match the actions exactly.
+	case path == "/neighbors/add":
+		return "/neighbors/add", service.handleNeighborsAdd
+	case path == "/neighbors/renew":
+		return "/neighbors/renew", service.handleNeighborsRenew
+	case path == "/neighbors/remove":
+		return "/neighbors/remove", service.handleNeighborsRemove
+	case path == "/neighbors" || path == "/neighbors/":
+		return "/neighbors", service.handleNeighborsList
+	case strings.HasPrefix(path, "/neighbors/"):
+		return "/neighbors/{id}", service.handleNeighbor
 	default:
-		http.Error(w, "No such route.", http.StatusNotFound)
+		return "unknown", func(w http.ResponseWriter, req *http.Request) {
+			http.Error(w, "No such route.", http.StatusNotFound)
+		}
 	}
 }
 
 func (service *SimulatedService) handleHealthCheck(w http.ResponseWriter, req *http.Request) {
 	var load, err = service.getLoad(req)
 	if err != nil {
+		if service.StrictHealth {
+			http.Error(w, fmt.Sprintf("Error parsing load param: %v", html.EscapeString(err.Error())), http.StatusBadRequest)
+			return
+		}
 		fmt.Fprintf(w, "Error parsing load param: %v", html.EscapeString(err.Error()))
 		return
 	}
@@ -89,6 +303,11 @@ func (service *SimulatedService) handleHealthCheck(w http.ResponseWriter, req *h
 	var responseBody = HealthCheckResponse{
 		Alive:        alive,
 		AvailableCPU: fmt.Sprintf("%.0f", 100*service.AvailableCPU()),
+		Stolen:       service.Stolen(),
+	}
+	// In strict mode, a dead service fails HTTP health checks too.
+	if service.StrictHealth && !alive {
+		w.WriteHeader(http.StatusServiceUnavailable)
 	}
 	err = encoder.Encode(responseBody)
 	if err != nil {
@@ -96,21 +315,102 @@ func (service *SimulatedService) handleHealthCheck(w http.ResponseWriter, req *h
 	}
 }
 
+// handleReadiness reports whether the service should be sent more traffic.
+// Unlike liveness, it always maps to real status codes.
+func (service *SimulatedService) handleReadiness(w http.ResponseWriter, req *http.Request) {
+	var load, err = service.getLoad(req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error parsing load param: %v", html.EscapeString(err.Error())), http.StatusBadRequest)
+		return
+	}
+	var ready = service.IsReady(load)
+	var responseBody = ReadinessResponse{
+		Ready:     ready,
+		Load:      load,
+		SoftLimit: service.ModifiedSoftLimit(),
+	}
+	if !ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	var encoder = json.NewEncoder(w)
+	err = encoder.Encode(responseBody)
+	if err != nil {
+		http.Error(w, "Error when writing response.", http.StatusInternalServerError)
+	}
+}
+
+func (service *SimulatedService) handleLoad(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+	case http.MethodPost, http.MethodPut:
+		var load, err = strconv.ParseUint(req.FormValue("load"), 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Error parsing load param: %v", html.EscapeString(err.Error())), http.StatusBadRequest)
+			return
+		}
+		service.SetLoad(load)
+	case http.MethodDelete:
+		service.ClearLoad()
+	default:
+		http.Error(w, "Method not allowed.", http.StatusMethodNotAllowed)
+		return
+	}
+	var load, explicit = service.load.Load(time.Now())
+	var source = "measured"
+	if explicit {
+		source = "set"
+	}
+	var encoder = json.NewEncoder(w)
+	var responseBody = LoadResponse{Load: load, Source: source}
+	var err = encoder.Encode(responseBody)
+	if err != nil {
+		http.Error(w, "Error when writing response.", http.StatusInternalServerError)
+	}
+}
+
 func (service *SimulatedService) handleNeighborsAdd(w http.ResponseWriter, req *http.Request) {
-	var previousStolenCPU = service.StolenCPU // keep a copy for reporting
-	var cpu, err = service.getCPU(req)
+	var resources, err = getResources(req)
+	if err != nil {
+		fmt.Fprintf(w, "Error parsing resource params: %v", html.EscapeString(err.Error()))
+		return
+	}
+	ttl, err := service.getTTL(req)
+	if err != nil {
+		fmt.Fprintf(w, "Error parsing TTL param: %v", html.EscapeString(err.Error()))
+		return
+	}
+	qos, err := parseQoS(req.FormValue("qos"))
 	if err != nil {
-		fmt.Fprintf(w, "Error parsing CPU param: %v", html.EscapeString(err.Error()))
+		http.Error(w, html.EscapeString(err.Error()), http.StatusBadRequest)
 		return
 	}
-	// Now that we've fetched the CPU, we need to update our stolen CPU counter
-	// with this new value.
-	atomic.AddUint64(&service.StolenCPU, cpu)
+	// Now that we've fetched the resources, we need to update our stolen resources
+	// with these new values. They are held by a lease until it's removed or expires.
+	neighbor, previous, err := service.AddNeighbor(Neighbor{
+		Address:   req.RemoteAddr,
+		Resources: resources,
+		Label:     req.FormValue("label"),
+		JobID:     req.FormValue("job_id"),
+		QoS:       qos,
+	}, ttl)
+	if rejected, ok := err.(*AdmissionError); ok {
+		service.metrics.ObserveNeighborCall("reject")
+		writeAdmissionError(w, rejected)
+		return
+	} else if err != nil {
+		http.Error(w, html.EscapeString(err.Error()), http.StatusInternalServerError)
+		return
+	}
+	service.metrics.ObserveNeighborCall("add")
 	// Response with success.
 	var encoder = json.NewEncoder(w)
+	var stolen = service.Stolen()
 	var responseBody = NeighborAddResponse{
-		PreviousStolenCPU: previousStolenCPU,
-		StolenCPU:         service.StolenCPU,
+		PreviousStolenCPU: previous.CPU,
+		StolenCPU:         stolen.CPU,
+		LeaseID:           neighbor.ID,
+		ExpiresAt:         neighbor.ExpiresAt,
+		StolenResources:   stolen,
 	}
 	err = encoder.Encode(responseBody)
 	if err != nil {
@@ -118,21 +418,108 @@ func (service *SimulatedService) handleNeighborsAdd(w http.ResponseWriter, req *
 	}
 }
 
-func (service *SimulatedService) handleNeighborsRemove(w http.ResponseWriter, req *http.Request) {
-	var cpu, err = service.getCPU(req)
+func (service *SimulatedService) handleNeighborsRenew(w http.ResponseWriter, req *http.Request) {
+	var ttl, err = service.getTTL(req)
 	if err != nil {
-		fmt.Fprintf(w, "Error parsing CPU param: %v", html.EscapeString(err.Error()))
+		fmt.Fprintf(w, "Error parsing TTL param: %v", html.EscapeString(err.Error()))
+		return
+	}
+	var neighbor, ok = service.RenewNeighbor(req.FormValue("id"), ttl)
+	if !ok {
+		http.Error(w, "No such lease.", http.StatusNotFound)
 		return
 	}
-	// Decrease the value of the stolen CPU as per https://golang.org/pkg/sync/atomic/#AddUint64
-	atomic.AddUint64(&service.StolenCPU, ^(cpu - 1))
+	service.metrics.ObserveNeighborCall("renew")
+	var encoder = json.NewEncoder(w)
+	var responseBody = NeighborRenewResponse{
+		LeaseID:   neighbor.ID,
+		ExpiresAt: neighbor.ExpiresAt,
+	}
+	err = encoder.Encode(responseBody)
+	if err != nil {
+		http.Error(w, "Error when writing response.", http.StatusInternalServerError)
+	}
+}
+
+func (service *SimulatedService) handleNeighborsRemove(w http.ResponseWriter, req *http.Request) {
+	var neighbor Neighbor
+	var ok bool
+	// Prefer removing by lease ID. Older neighbors only know their CPU,
+	// so fall back to releasing a lease with a matching CPU.
+	if id := req.FormValue("id"); id != "" {
+		neighbor, ok = service.RemoveNeighbor(id)
+	} else {
+		var cpu, err = service.getCPU(req)
+		if err != nil {
+			fmt.Fprintf(w, "Error parsing CPU param: %v", html.EscapeString(err.Error()))
+			return
+		}
+		neighbor, ok = service.RemoveNeighborByCPU(cpu)
+	}
+	if !ok {
+		http.Error(w, "No such lease.", http.StatusNotFound)
+		return
+	}
+	service.metrics.ObserveNeighborCall("remove")
 	// Response with success.
 	var encoder = json.NewEncoder(w)
 	var responseBody = NeighborRemoveResponse{
-		RestoredCPU: cpu,
-		StolenCPU:   service.StolenCPU,
+		RestoredCPU:       neighbor.CPU,
+		StolenCPU:         service.StolenCPU(),
+		RestoredResources: neighbor.Resources,
+		StolenResources:   service.Stolen(),
 	}
-	err = encoder.Encode(responseBody)
+	var err = encoder.Encode(responseBody)
+	if err != nil {
+		http.Error(w, "Error when writing response.", http.StatusInternalServerError)
+	}
+}
+
+func (service *SimulatedService) handleNeighborsList(w http.ResponseWriter, req *http.Request) {
+	var encoder = json.NewEncoder(w)
+	var stolen = service.Stolen()
+	var responseBody = NeighborListResponse{
+		Neighbors: service.Neighbors(),
+		StolenCPU: stolen.CPU,
+		Stolen:    stolen,
+	}
+	var err = encoder.Encode(responseBody)
+	if err != nil {
+		http.Error(w, "Error when writing response.", http.StatusInternalServerError)
+	}
+}
+
+// handleNeighbor describes or removes the neighbor whose ID is the last
+// element of the URL path.
+func (service *SimulatedService) handleNeighbor(w http.ResponseWriter, req *http.Request) {
+	var id = strings.TrimPrefix(req.URL.Path, "/neighbors/")
+	var responseBody interface{}
+	var ok bool
+	switch req.Method {
+	case http.MethodGet:
+		responseBody, ok = service.Neighbor(id)
+	case http.MethodDelete:
+		var neighbor Neighbor
+		neighbor, ok = service.RemoveNeighbor(id)
+		responseBody = NeighborRemoveResponse{
+			RestoredCPU:       neighbor.CPU,
+			StolenCPU:         service.StolenCPU(),
+			RestoredResources: neighbor.Resources,
+			StolenResources:   service.Stolen(),
+		}
+	default:
+		http.Error(w, "Method not allowed.", http.StatusMethodNotAllowed)
+		return
+	}
+	if !ok {
+		http.Error(w, "No such neighbor.", http.StatusNotFound)
+		return
+	}
+	if req.Method == http.MethodDelete {
+		service.metrics.ObserveNeighborCall("remove")
+	}
+	var encoder = json.NewEncoder(w)
+	var err = encoder.Encode(responseBody)
 	if err != nil {
 		http.Error(w, "Error when writing response.", http.StatusInternalServerError)
 	}
@@ -148,11 +535,33 @@ func (service *SimulatedService) getCPU(req *http.Request) (uint64, error) {
 	return strconv.ParseUint(cpu, 10, 64)
 }
 
+// getTTL returns the lease duration requested by a noisy neighbor, in seconds.
+// If no TTL is provided, the default lease TTL is used.
+func (service *SimulatedService) getTTL(req *http.Request) (time.Duration, error) {
+	var ttl = req.FormValue("ttl")
+	if ttl == "" {
+		return defaultLeaseTTL, nil
+	}
+	var seconds, err = strconv.ParseUint(ttl, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if seconds == 0 {
+		return 0, errors.New("ttl must be greater than zero")
+	}
+	return leaseTTL(seconds)
+}
+
 // getLoad returns the value of the load provided to this server within the
 // last second. It's fetched from the request's URL parameters.
+// If the request doesn't provide a load, the load held by the server is used instead.
 func (service *SimulatedService) getLoad(req *http.Request) (uint64, error) {
 	// Fetch the simulated load.
 	var load = req.FormValue("load")
+	if load == "" {
+		var current, _ = service.load.Load(time.Now())
+		return current, nil
+	}
 	return strconv.ParseUint(load, 10, 64)
 }
 
@@ -163,6 +572,8 @@ func (service *SimulatedService) handleThroughput(w http.ResponseWriter, req *ht
 		service.handleThroughputGET(w, req)
 	case http.MethodPost:
 		service.handleThroughputPOST(w, req)
+	default:
+		http.Error(w, "Method not allowed.", http.StatusMethodNotAllowed)
 	}
 }
 
@@ -182,50 +593,229 @@ func (service *SimulatedService) handleThroughputGET(w http.ResponseWriter, req
 	}
 }
 
-func (service *SimulatedService) CalculateThroughput(load uint64) uint64 {
-	var throughput uint64
-	if load <= service.AvailableThroughput() {
-		throughput = load
-	} else if load <= service.ModifiedSoftLimit() {
-		throughput = service.AvailableThroughput()
-	} else if load <= service.ModifiedHardLimit() {
-		throughput = service.degradedThroughput(load)
-	} else {
-		throughput = 0
+func (service *SimulatedService) handleLatency(w http.ResponseWriter, req *http.Request) {
+	var load, err = service.getLoad(req)
+	if err != nil {
+		fmt.Fprintf(w, "Error parsing load param: %v", html.EscapeString(err.Error()))
+		return
+	}
+	var encoder = json.NewEncoder(w)
+	var responseBody = LatencyResponse{
+		Load:  load,
+		Alive: service.IsAlive(load),
+		P50:   toMillis(service.LatencyPercentile(load, 0.5)),
+		P90:   toMillis(service.LatencyPercentile(load, 0.9)),
+		P99:   toMillis(service.LatencyPercentile(load, 0.99)),
+		P999:  toMillis(service.LatencyPercentile(load, 0.999)),
 	}
+	err = encoder.Encode(responseBody)
+	if err != nil {
+		http.Error(w, "Error when writing response.", http.StatusInternalServerError)
+	}
+}
 
-	return throughput
+// toMillis converts a duration to fractional milliseconds for reporting.
+func toMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// CalculateThroughput returns the number of requests this service completes
+// per second under the given load, as decided by its throughput model.
+// In real mode, it's the number of requests to /v1/work completed over the last second.
+func (service *SimulatedService) CalculateThroughput(load uint64) uint64 {
+	return service.explainThroughput(load, service.serveModel).Throughput
 }
 
-// degradedThroughput returns a value between 50% and 75% of the available throughput.
-func (service *SimulatedService) degradedThroughput(load uint64) uint64 {
-	var total = service.AvailableThroughput()
-	var offset = total / 2
-	var rngBound = total / 4
-	var rng = rand.Int63n(int64(rngBound))
-	return offset + uint64(rng)
+// Seed resets the service's random number generators with the given seed,
+// so that a scenario can be replayed exactly.
+func (service *SimulatedService) Seed(seed int64) {
+	service.rng.Seed(seed)
+	service.sampleRNG.Seed(seed)
+}
+
+// Model returns the throughput model used by this service.
+func (service *SimulatedService) Model() ThroughputModel {
+	return service.Snapshot().Model
+}
+
+// SetModel replaces the throughput model used by this service.
+func (service *SimulatedService) SetModel(model ThroughputModel) {
+	service.updateState(func(state *State) error {
+		state.Model = model
+		return nil
+	})
+}
+
+// SetSensitivity replaces how strongly the theft of each resource slows down this service.
+func (service *SimulatedService) SetSensitivity(sensitivity Sensitivity) error {
+	if err := sensitivity.Validate(); err != nil {
+		return err
+	}
+	service.updateState(func(state *State) error {
+		state.Sensitivity = sensitivity
+		return nil
+	})
+	return nil
 }
 
 func (service *SimulatedService) handleThroughputPOST(w http.ResponseWriter, req *http.Request) {
-	panic("TODO handle updating throughput")
+	var update LimitsUpdate
+	var err error
+	if update.MaxThroughput, err = getOptionalUint(req, "throughput"); err != nil {
+		http.Error(w, fmt.Sprintf("Error parsing throughput param: %v", html.EscapeString(err.Error())), http.StatusBadRequest)
+		return
+	}
+	if update.SoftLimit, err = getOptionalUint(req, "soft_limit"); err != nil {
+		http.Error(w, fmt.Sprintf("Error parsing soft_limit param: %v", html.EscapeString(err.Error())), http.StatusBadRequest)
+		return
+	}
+	if update.HardLimit, err = getOptionalUint(req, "hard_limit"); err != nil {
+		http.Error(w, fmt.Sprintf("Error parsing hard_limit param: %v", html.EscapeString(err.Error())), http.StatusBadRequest)
+		return
+	}
+	var state State
+	if state, err = service.UpdateLimits(update); err != nil {
+		http.Error(w, html.EscapeString(err.Error()), http.StatusBadRequest)
+		return
+	}
+	// Reply with the new state of the server.
+	var encoder = json.NewEncoder(w)
+	var responseBody = ThroughputPOSTResponse{
+		Throughput:       state.MaxThroughput,
+		RequestSoftLimit: state.SoftLimit,
+		RequestHardLimit: state.HardLimit,
+	}
+	err = encoder.Encode(responseBody)
+	if err != nil {
+		http.Error(w, "Error when writing response.", http.StatusInternalServerError)
+	}
+}
+
+// getOptionalUint returns the value of the named parameter within the request,
+// or nil if the parameter was not provided.
+func getOptionalUint(req *http.Request, name string) (*uint64, error) {
+	var value = req.FormValue(name)
+	if value == "" {
+		return nil, nil
+	}
+	var parsed, err = strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+// Reconfigure replaces the limits, throughput model and sensitivity of this service
+// at once, so no request sees the new limits with the old model or vice versa.
+func (service *SimulatedService) Reconfigure(throughput, soft, hard uint64, model ThroughputModel, sensitivity Sensitivity) error {
+	if err := validateLimits(throughput, soft, hard); err != nil {
+		return err
+	}
+	if err := sensitivity.Validate(); err != nil {
+		return err
+	}
+	var _, after, _ = service.updateState(func(state *State) error {
+		state.MaxThroughput, state.SoftLimit, state.HardLimit = throughput, soft, hard
+		state.Model = model
+		state.Sensitivity = sensitivity
+		return nil
+	})
+	service.recordLimits(api.EventReconfigured, after, "Config reloaded with "+describeLimits(after))
+	return nil
+}
+
+// validateLimits returns an error if the limits can't describe a service.
+func validateLimits(throughput, soft, hard uint64) error {
+	if throughput == 0 {
+		return errors.New("throughput must be greater than zero")
+	}
+	if soft > hard {
+		return fmt.Errorf("soft limit (%d) must not exceed hard limit (%d)", soft, hard)
+	}
+	return nil
 }
 
-// IsAlive returns true if the server hasn't fallen over from too much load.
+// Limits returns the max throughput, soft limit, and hard limit of this service,
+// before noisy neighbors have been accounted for.
+func (service *SimulatedService) Limits() (throughput, soft, hard uint64) {
+	var state = service.Snapshot()
+	return state.MaxThroughput, state.SoftLimit, state.HardLimit
+}
+
+// A LimitsUpdate changes some of the limits of a service.
+// Limits which are nil keep their current value.
+type LimitsUpdate struct {
+	MaxThroughput *uint64
+	SoftLimit     *uint64
+	HardLimit     *uint64
+}
+
+// UpdateLimits merges the update into the current limits, and returns the new state.
+// The throughput must be non-zero, and the soft limit cannot exceed the hard limit.
+// The merge and validation happen under the state lock, so concurrent updates
+// of different limits don't overwrite each other, nor pass validation against stale limits.
+func (service *SimulatedService) UpdateLimits(update LimitsUpdate) (State, error) {
+	var before, after, err = service.updateState(func(state *State) error {
+		var throughput, soft, hard = state.MaxThroughput, state.SoftLimit, state.HardLimit
+		if update.MaxThroughput != nil {
+			throughput = *update.MaxThroughput
+		}
+		if update.SoftLimit != nil {
+			soft = *update.SoftLimit
+		}
+		if update.HardLimit != nil {
+			hard = *update.HardLimit
+		}
+		if err := validateLimits(throughput, soft, hard); err != nil {
+			return err
+		}
+		state.MaxThroughput, state.SoftLimit, state.HardLimit = throughput, soft, hard
+		return nil
+	})
+	if err != nil {
+		return before, err
+	}
+	if describeLimits(before) != describeLimits(after) {
+		service.recordLimits(api.EventLimitsChanged, after,
+			fmt.Sprintf("Limits changed from %s to %s", describeLimits(before), describeLimits(after)))
+	}
+	return after, nil
+}
+
+// IsAlive returns true if the server hasn't fallen over from too much load,
+// i.e. the load is within the hard limit once noisy neighbors are accounted for.
 func (service *SimulatedService) IsAlive(load uint64) bool {
-	return service.RequestHardLimit >= load
+	return !service.IsDying() && !service.Snapshot().Overloaded(load)
+}
+
+// IsReady returns true if the service can take the load without degrading,
+// i.e. the load is within the soft limit once noisy neighbors are accounted for.
+// A service which is shutting down is never ready.
+func (service *SimulatedService) IsReady(load uint64) bool {
+	return !service.IsDying() && !service.IsDraining() && service.ModifiedSoftLimit() >= load
 }
 
 // AvailableCPU returns, as a fraction from 0 to 1, the amount of CPU
 // available to this service. Noisy neighbors reduce the amount of CPU available.
 func (service *SimulatedService) AvailableCPU() float64 {
-	return float64(100-service.StolenCPU) / 100.0
+	return service.Snapshot().AvailableCPU()
+}
+
+// StolenCPU returns the total CPU, as a percentage, held by noisy neighbors.
+func (service *SimulatedService) StolenCPU() uint64 {
+	return service.Snapshot().Stolen.CPU
+}
+
+// Stolen returns the total of each resource, as a percentage, held by noisy neighbors.
+func (service *SimulatedService) Stolen() Resources {
+	return service.Snapshot().Stolen
 }
 
 // AvailableThroughput returns the number of requests per second processable
-// by this service. It's value is the max throughput modified by the available CPU.
-// If noisy neighbors steal CPU, then the available CPU decreases.
+// by this service. It's value is the max throughput modified by the stolen resources.
+// If noisy neighbors steal CPU, memory bandwidth, disk I/O or network, it decreases.
 func (service *SimulatedService) AvailableThroughput() uint64 {
-	return service.scaleDown(service.MaxThroughput)
+	return service.Snapshot().AvailableThroughput()
 }
 
 // ModifiedSoftLimit returns the new soft limit for this server once
@@ -233,7 +823,7 @@ func (service *SimulatedService) AvailableThroughput() uint64 {
 // by other services in the same pod interfering with it.
 // If noisy neighbors steal CPU, then the available CPU decreases.
 func (service *SimulatedService) ModifiedSoftLimit() uint64 {
-	return service.scaleDown(service.RequestSoftLimit)
+	return service.Snapshot().ModifiedSoftLimit()
 }
 
 // MModifiedHardLimit returns the new hard limit for this server once
@@ -241,14 +831,5 @@ func (service *SimulatedService) ModifiedSoftLimit() uint64 {
 // by other services in the same pod interfering with it.
 // If noisy neighbors steal CPU, then the available CPU decreases.
 func (service *SimulatedService) ModifiedHardLimit() uint64 {
-	return service.scaleDown(service.RequestHardLimit)
-}
-
-// scaleDown takes the provided metric (throughput, soft limit, hard limit)
-// and adjusts it to reflect the new limit provided by the noisy neighbor.
-func (service *SimulatedService) scaleDown(metric uint64) uint64 {
-	// Scale down the metric in proportion to CPU availability.
-	var scaledMetric = float64(metric) * service.AvailableCPU()
-	// Round, and then cast.
-	return uint64(math.Round(scaledMetric))
+	return service.Snapshot().ModifiedHardLimit()
 }
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRoute(t *testing.T) {
+	var service = NewSimulatedService(1000, 1500, 2000)
+	defer service.Close()
+	var tests = []struct {
+		path, want string
+	}{
+		{"/healthz", "/healthz"},
+		{"/load", "/load"},
+		{"/metrics", "/metrics"},
+		{"/metrics/throughput", "/metrics/throughput"},
+		{"/neighbors", "/neighbors"},
+		{"/neighbors/", "/neighbors"},
+		{"/neighbors/add", "/neighbors/add"},
+		{"/neighbors/renew", "/neighbors/renew"},
+		{"/neighbors/remove", "/neighbors/remove"},
+		{"/neighbors/0123456789abcdef", "/neighbors/{id}"},
+		// IDs are hex, so they can start with the name of an action.
+		{"/neighbors/add0123456789abc", "/neighbors/{id}"},
+		{"/neighbors/add/", "/neighbors/{id}"},
+		{"/v1/neighbors/add0123456789abc", "/v1/neighbors/{id}"},
+		{"/v1/neighbors/0123456789abcdef/renew", "/v1/neighbors/{id}/renew"},
+		{"/nowhere", "unknown"},
+	}
+	for _, test := range tests {
+		if got, _ := service.route(test.path); got != test.want {
+			t.Errorf("route(%q) = %q, want %q", test.path, got, test.want)
+		}
+	}
+}
+
+func TestV1SetLoadRequiresALoad(t *testing.T) {
+	var tests = []struct {
+		body string
+		want int
+	}{
+		{``, http.StatusBadRequest},
+		{`{}`, http.StatusBadRequest},
+		{`{"load": null}`, http.StatusBadRequest},
+		{`{"load": 0}`, http.StatusOK},
+		{`{"load": 5}`, http.StatusOK},
+	}
+	for _, test := range tests {
+		var service = NewSimulatedService(1000, 1500, 2000)
+		defer service.Close()
+		var recorder = httptest.NewRecorder()
+		service.ServeHTTP(recorder, httptest.NewRequest("POST", "/v1/load", strings.NewReader(test.body)))
+		if recorder.Code != test.want {
+			t.Errorf("POST /v1/load with %q = %d, want %d", test.body, recorder.Code, test.want)
+		}
+		if _, set := service.load.Load(time.Now()); set != (test.want == http.StatusOK) {
+			t.Errorf("POST /v1/load with %q set the load: %v, want %v", test.body, set, test.want == http.StatusOK)
+		}
+	}
+}
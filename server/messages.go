@@ -1,9 +1,26 @@
 package main
 
+import "time"
+
 // HealthCheckResponse tells the client if this server is alive or dead.
 type HealthCheckResponse struct {
-	Alive        bool   `json:"alive"`
-	AvailableCPU string `json:"avaiable_cpu"`
+	Alive        bool      `json:"alive"`
+	AvailableCPU string    `json:"avaiable_cpu"`
+	Stolen       Resources `json:"stolen"`
+}
+
+// LoadResponse returns the load held by this server, and whether it
+// was "set" by a caller or "measured" from the requests it received.
+type LoadResponse struct {
+	Load   uint64 `json:"load"`
+	Source string `json:"source"`
+}
+
+// ReadinessResponse tells the client if this server should receive more traffic.
+type ReadinessResponse struct {
+	Ready     bool   `json:"ready"`
+	Load      uint64 `json:"load"`
+	SoftLimit uint64 `json:"soft_limit"`
 }
 
 // ThroughputGETResponse returns the throughput at this point in time.
@@ -12,6 +29,18 @@ type ThroughputGETResponse struct {
 	Throughput uint64 `json:"throughput"`
 }
 
+// LatencyResponse returns the simulated latency percentiles,
+// in milliseconds, under the given load.
+// A dead server reports the request timeout for every percentile.
+type LatencyResponse struct {
+	Load  uint64  `json:"load"`
+	Alive bool    `json:"alive"`
+	P50   float64 `json:"p50_ms"`
+	P90   float64 `json:"p90_ms"`
+	P99   float64 `json:"p99_ms"`
+	P999  float64 `json:"p999_ms"`
+}
+
 // ThroughputPOSTResponse returns the new state of the server
 // after the throughput has been changed.
 type ThroughputPOSTResponse struct {
@@ -26,6 +55,28 @@ type ThroughputPOSTResponse struct {
 type NeighborAddResponse struct {
 	PreviousStolenCPU uint64
 	StolenCPU         uint64
+	LeaseID           string
+	ExpiresAt         time.Time
+	// StolenResources covers every resource, including CPU.
+	StolenResources Resources
+}
+
+// AdmissionRejectedResponse is the JSON payload returned with a 409
+// when a noisy neighbor would push a stolen resource past its QoS class's ceiling.
+type AdmissionRejectedResponse struct {
+	Error     string `json:"error"`
+	QoS       string `json:"qos"`
+	Resource  string `json:"resource"`
+	Requested uint64 `json:"requested"`
+	Stolen    uint64 `json:"stolen"`
+	Ceiling   uint64 `json:"ceiling"`
+}
+
+// NeighborRenewResponse is the JSON payload returned
+// when a noisy neighbor renews its lease.
+type NeighborRenewResponse struct {
+	LeaseID   string
+	ExpiresAt time.Time
 }
 
 // NeighborRemoveResponse is the JSON payload returned
@@ -34,4 +85,15 @@ type NeighborAddResponse struct {
 type NeighborRemoveResponse struct {
 	StolenCPU   uint64
 	RestoredCPU uint64
+	// StolenResources and RestoredResources cover every resource, including CPU.
+	StolenResources   Resources
+	RestoredResources Resources
+}
+
+// NeighborListResponse is the JSON payload listing every
+// noisy neighbor currently stealing CPU from this service.
+type NeighborListResponse struct {
+	Neighbors []Neighbor `json:"neighbors"`
+	StolenCPU uint64     `json:"stolen_cpu"`
+	Stolen    Resources  `json:"stolen"`
 }
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Capacity describes how much load a service can take once noisy neighbors
+// have been accounted for. It's the input to a ThroughputModel.
+type Capacity struct {
+	// Throughput is the number of requests per second processable by the service.
+	Throughput uint64
+	// SoftLimit is the load at which the service starts to degrade.
+	SoftLimit uint64
+	// HardLimit is the load past which the service falls over.
+	HardLimit uint64
+}
+
+// A ThroughputModel decides how a service's throughput responds to load.
+// Models are only consulted for loads up to the hard limit;
+// past the hard limit the service is dead and its throughput is zero.
+// Any randomness must come from the provided generator, so runs are reproducible.
+// Explain returns the throughput along with how it was reached.
+type ThroughputModel interface {
+	Explain(load uint64, capacity Capacity, rng *rand.Rand) Explanation
+}
+
+// An Explanation describes how a ThroughputModel arrived at a throughput.
+type Explanation struct {
+	// Model is the name of the model: step, mm1, mmc or usl.
+	Model string
+	// Regime names the part of the model's curve the load fell in.
+	Regime     string
+	Throughput uint64
+	// Values holds the intermediate values of the calculation, by name.
+	Values map[string]float64
+	// Draw is set when the throughput was drawn at random.
+	Draw *Draw
+}
+
+// A Draw is a value drawn at random from Min up to, but not including, Max.
+type Draw struct {
+	Min, Max, Value uint64
+}
+
+// NewThroughputModel returns the model with the given name, configured
+// with the given parameters. Unused parameters are ignored by each model.
+func NewThroughputModel(name string, servers, queueLength uint64, contention, coherency float64) (ThroughputModel, error) {
+	switch name {
+	case "", "step":
+		return StepModel{}, nil
+	case "mm1":
+		return QueueModel{Servers: 1, QueueLength: queueLength}, nil
+	case "mmc":
+		if servers == 0 {
+			return nil, fmt.Errorf("the %q model needs at least one server", name)
+		}
+		return QueueModel{Servers: servers, QueueLength: queueLength}, nil
+	case "usl":
+		if contention < 0 || contention >= 1 || coherency <= 0 {
+			return nil, fmt.Errorf("the %q model needs contention in [0, 1) and positive coherency", name)
+		}
+		return USLModel{Contention: contention, Coherency: coherency}, nil
+	default:
+		return nil, fmt.Errorf("unknown throughput model %q", name)
+	}
+}
+
+// modelName returns the name of the model, as NewThroughputModel knows it.
+func modelName(model ThroughputModel) string {
+	switch model := model.(type) {
+	case StepModel:
+		return "step"
+	case QueueModel:
+		if model.Servers <= 1 {
+			return "mm1"
+		}
+		return "mmc"
+	case USLModel:
+		return "usl"
+	default:
+		return fmt.Sprintf("%T", model)
+	}
+}
+
+// StepModel is the original four-regime model. Throughput follows the load
+// up to the available throughput, stays flat up to the soft limit, and falls
+// to a random 50-75% of the available throughput up to the hard limit.
+type StepModel struct{}
+
+// Explain fulfills the ThroughputModel interface. The regime is healthy up to
+// the available throughput, saturated up to the soft limit, and degraded after that.
+func (model StepModel) Explain(load uint64, capacity Capacity, rng *rand.Rand) Explanation {
+	var explanation = Explanation{Model: modelName(model)}
+	switch {
+	case load <= capacity.Throughput:
+		explanation.Regime, explanation.Throughput = "healthy", load
+	case load <= capacity.SoftLimit:
+		explanation.Regime, explanation.Throughput = "saturated", capacity.Throughput
+	default:
+		explanation.Regime = "degraded"
+		explanation.Throughput, explanation.Draw = degradedThroughput(capacity.Throughput, rng)
+	}
+	return explanation
+}
+
+// degradedThroughput returns a value between 50% and 75% of the available throughput,
+// and the draw it came from, if any.
+func degradedThroughput(total uint64, rng *rand.Rand) (uint64, *Draw) {
+	var offset = total / 2
+	var rngBound = total / 4
+	// With little throughput left, there's no band to draw from.
+	if rngBound == 0 {
+		return offset, nil
+	}
+	var value = offset + uint64(rng.Int63n(int64(rngBound)))
+	return value, &Draw{Min: offset, Max: offset + rngBound, Value: value}
+}
+
+// QueueModel treats the service as an M/M/c/K queue: requests arrive at the
+// offered load, and are served by Servers workers which together complete
+// the available throughput. Up to QueueLength requests can wait or be served
+// at once; requests arriving at a full queue are dropped.
+// With a single server, this is an M/M/1/K queue.
+type QueueModel struct {
+	Servers     uint64
+	QueueLength uint64
+}
+
+// maxQueueSize bounds the servers and queue length of a QueueModel,
+// since Explain works through every possible length of the queue.
+const maxQueueSize = 10000
+
+// Explain fulfills the ThroughputModel interface. The regime is idle without
+// load or throughput, healthy while the queue rarely fills, and dropping once
+// it fills for more than one request in a hundred.
+func (model QueueModel) Explain(load uint64, capacity Capacity, rng *rand.Rand) Explanation {
+	var explanation = Explanation{Model: modelName(model), Regime: "idle", Values: map[string]float64{}}
+	if load == 0 || capacity.Throughput == 0 {
+		return explanation
+	}
+	var servers = model.Servers
+	if servers == 0 {
+		servers = 1
+	}
+	// The system can never hold fewer requests than it has servers.
+	var size = model.QueueLength
+	if size < servers {
+		size = servers
+	}
+	// The offered traffic, in Erlangs.
+	var perServer = float64(capacity.Throughput) / float64(servers)
+	var traffic = float64(load) / perServer
+
+	// The probability of n requests in the system is proportional to
+	// traffic^n / n! for n < c, and traffic^n / (c! c^(n-c)) after that.
+	// Work with logarithms, since these terms overflow for long queues.
+	var logTerms = make([]float64, size+1)
+	var logTraffic = math.Log(traffic)
+	for n := uint64(1); n <= size; n++ {
+		var busy = n
+		if busy > servers {
+			busy = servers
+		}
+		logTerms[n] = logTerms[n-1] + logTraffic - math.Log(float64(busy))
+	}
+	var blocking = math.Exp(logTerms[size] - logSumExp(logTerms))
+	explanation.Throughput = uint64(math.Round(float64(load) * (1 - blocking)))
+	explanation.Regime = "healthy"
+	if blocking > droppingBlocking {
+		explanation.Regime = "dropping"
+	}
+	explanation.Values["servers"] = float64(servers)
+	explanation.Values["system_size"] = float64(size)
+	explanation.Values["per_server_throughput"] = perServer
+	explanation.Values["offered_traffic_erlangs"] = traffic
+	explanation.Values["blocking_probability"] = blocking
+	return explanation
+}
+
+// droppingBlocking is the blocking probability past which a queue is said to be dropping requests.
+const droppingBlocking = 0.01
+
+// logSumExp returns log(Σ exp(x)) without overflowing.
+func logSumExp(xs []float64) float64 {
+	var max = math.Inf(-1)
+	for _, x := range xs {
+		max = math.Max(max, x)
+	}
+	var sum float64
+	for _, x := range xs {
+		sum += math.Exp(x - max)
+	}
+	return max + math.Log(sum)
+}
+
+// USLModel follows the Universal Scalability Law, where contention (σ) and
+// coherency (κ) costs bend the throughput curve as concurrency N grows:
+//
+//	X(N) = N / (1 + σ(N-1) + κN(N-1))
+//
+// The curve peaks at N* = sqrt((1-σ)/κ) and declines after that.
+// The peak is pinned to the available throughput, so a load equal to
+// the available throughput runs at N*. Throughput never exceeds the offered load.
+type USLModel struct {
+	Contention float64
+	Coherency  float64
+}
+
+// Explain fulfills the ThroughputModel interface. The regime is idle without
+// throughput, scaling up to the peak concurrency, and retrograde past it.
+func (model USLModel) Explain(load uint64, capacity Capacity, rng *rand.Rand) Explanation {
+	var explanation = Explanation{Model: modelName(model), Regime: "idle", Values: map[string]float64{}}
+	if capacity.Throughput == 0 {
+		return explanation
+	}
+	var peak = math.Sqrt((1 - model.Contention) / model.Coherency)
+	var concurrency = peak * float64(load) / float64(capacity.Throughput)
+	var throughput = float64(capacity.Throughput) * model.scale(concurrency) / model.scale(peak)
+	explanation.Throughput = uint64(math.Round(math.Min(throughput, float64(load))))
+	explanation.Regime = "scaling"
+	if concurrency > peak {
+		explanation.Regime = "retrograde"
+	}
+	explanation.Values["contention"] = model.Contention
+	explanation.Values["coherency"] = model.Coherency
+	explanation.Values["peak_concurrency"] = peak
+	explanation.Values["concurrency"] = concurrency
+	explanation.Values["curve_throughput"] = throughput
+	return explanation
+}
+
+// scale returns the relative capacity X(N) at the given concurrency.
+func (model USLModel) scale(concurrency float64) float64 {
+	var n = concurrency
+	return n / (1 + model.Contention*(n-1) + model.Coherency*n*(n-1))
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+// testCapacity is the capacity the models are tested with.
+var testCapacity = Capacity{Throughput: 1000, SoftLimit: 1500, HardLimit: 2000}
+
+func TestModelsNeverExceedTheLoad(t *testing.T) {
+	var models = []ThroughputModel{
+		StepModel{},
+		QueueModel{Servers: 1, QueueLength: 10},
+		QueueModel{Servers: 4, QueueLength: 100},
+		QueueModel{Servers: 8, QueueLength: 2},
+		USLModel{Contention: 0.05, Coherency: 0.02},
+		USLModel{Contention: 0, Coherency: 0.5},
+	}
+	var rng = NewRand(1)
+	for _, model := range models {
+		for load := uint64(0); load <= testCapacity.HardLimit; load += 50 {
+			if got := model.Explain(load, testCapacity, rng).Throughput; got > load {
+				t.Errorf("%+v: throughput %d under load %d exceeds the load", model, got, load)
+			}
+		}
+	}
+}
+
+func TestQueueModelMatchesMM1K(t *testing.T) {
+	// The blocking probability of an M/M/1/K queue with utilization ρ is
+	// (1-ρ)ρ^K / (1-ρ^(K+1)), or 1/(K+1) when ρ is 1.
+	var closedForm = func(rho float64, k uint64) float64 {
+		if rho == 1 {
+			return 1 / float64(k+1)
+		}
+		return (1 - rho) * math.Pow(rho, float64(k)) / (1 - math.Pow(rho, float64(k+1)))
+	}
+	var tests = []struct {
+		load, queueLength uint64
+	}{
+		{100, 1},
+		{500, 10},
+		{900, 10},
+		{1000, 10},
+		{1000, 100},
+		{1500, 5},
+		{2000, 50},
+	}
+	for _, test := range tests {
+		var model = QueueModel{Servers: 1, QueueLength: test.queueLength}
+		var explanation = model.Explain(test.load, testCapacity, nil)
+		var rho = float64(test.load) / float64(testCapacity.Throughput)
+		var want = closedForm(rho, test.queueLength)
+		if got := explanation.Values["blocking_probability"]; math.Abs(got-want) > 1e-9 {
+			t.Errorf("load %d, K=%d: blocking probability %v, want %v", test.load, test.queueLength, got, want)
+		}
+		var throughput = uint64(math.Round(float64(test.load) * (1 - want)))
+		if explanation.Throughput != throughput {
+			t.Errorf("load %d, K=%d: throughput %d, want %d", test.load, test.queueLength, explanation.Throughput, throughput)
+		}
+	}
+}
+
+func TestQueueModelRegimes(t *testing.T) {
+	var model = QueueModel{Servers: 4, QueueLength: 100}
+	var tests = []struct {
+		load   uint64
+		regime string
+	}{
+		{0, "idle"},
+		{500, "healthy"},
+		{2000, "dropping"},
+	}
+	for _, test := range tests {
+		if got := model.Explain(test.load, testCapacity, nil).Regime; got != test.regime {
+			t.Errorf("load %d: regime %q, want %q", test.load, got, test.regime)
+		}
+	}
+}
+
+func TestUSLModel(t *testing.T) {
+	var model = USLModel{Contention: 0.05, Coherency: 0.02}
+	var tests = []struct {
+		name     string
+		load     uint64
+		regime   string
+		min, max uint64
+	}{
+		{name: "idle capacity", load: 0, regime: "scaling", max: 0},
+		{name: "light load", load: 200, regime: "scaling", min: 1, max: 200},
+		// The peak is pinned to the available throughput.
+		{name: "at the peak", load: 1000, regime: "scaling", min: 1000, max: 1000},
+		{name: "past the peak", load: 1500, regime: "retrograde", min: 1, max: 999},
+		{name: "far past the peak", load: 2000, regime: "retrograde", min: 1, max: 999},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var explanation = model.Explain(test.load, testCapacity, nil)
+			if explanation.Regime != test.regime {
+				t.Errorf("regime %q, want %q", explanation.Regime, test.regime)
+			}
+			if explanation.Throughput < test.min || explanation.Throughput > test.max {
+				t.Errorf("throughput %d, want from %d to %d", explanation.Throughput, test.min, test.max)
+			}
+		})
+	}
+	// Past the peak, more load only brings less throughput.
+	var past, further = model.Explain(1500, testCapacity, nil), model.Explain(2000, testCapacity, nil)
+	if further.Throughput >= past.Throughput {
+		t.Errorf("throughput %d at load 2000 isn't below %d at load 1500", further.Throughput, past.Throughput)
+	}
+	if got := model.Explain(100, Capacity{}, nil).Regime; got != "idle" {
+		t.Errorf("regime without capacity %q, want idle", got)
+	}
+}
+
+func TestDegradedThroughput(t *testing.T) {
+	var rng = NewRand(1)
+	var tests = []struct {
+		total    uint64
+		min, max uint64
+		drawn    bool
+	}{
+		// With under 4 requests per second, there's no band to draw from.
+		{total: 0, min: 0, max: 0},
+		{total: 1, min: 0, max: 0},
+		{total: 3, min: 1, max: 1},
+		{total: 4, min: 2, max: 2, drawn: true},
+		{total: 1000, min: 500, max: 749, drawn: true},
+	}
+	for _, test := range tests {
+		for i := 0; i < 100; i++ {
+			var value, draw = degradedThroughput(test.total, rng)
+			if value < test.min || value > test.max {
+				t.Fatalf("degradedThroughput(%d) = %d, want from %d to %d", test.total, value, test.min, test.max)
+			}
+			if (draw != nil) != test.drawn {
+				t.Fatalf("degradedThroughput(%d) drew %+v, want a draw: %v", test.total, draw, test.drawn)
+			}
+			if draw != nil && draw.Value != value {
+				t.Fatalf("degradedThroughput(%d) = %d, but drew %d", test.total, value, draw.Value)
+			}
+		}
+	}
+}
+
+func TestSeededServicesServeTheSameThroughputs(t *testing.T) {
+	var first, second = NewSimulatedService(1000, 1500, 2000), NewSimulatedService(1000, 1500, 2000)
+	defer first.Close()
+	defer second.Close()
+	first.Seed(42)
+	second.Seed(42)
+	var distinct = make(map[uint64]bool)
+	// Past the soft limit, the step model draws the throughput at random.
+	for i := 0; i < 50; i++ {
+		var want, got = first.CalculateThroughput(1800), second.CalculateThroughput(1800)
+		if got != want {
+			t.Fatalf("throughput %d = %d, want %d as served by the service with the same seed", i, got, want)
+		}
+		distinct[got] = true
+	}
+	if len(distinct) < 2 {
+		t.Errorf("every degraded throughput was %v, want them drawn at random", distinct)
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
+)
+
+const (
+	// defaultLeaseTTL is used when a neighbor doesn't ask for a TTL.
+	defaultLeaseTTL = 30 * time.Second
+	// maxLeaseTTL is the longest lease a neighbor may ask for.
+	maxLeaseTTL = 24 * time.Hour
+	// reapInterval is how often expired leases are checked for.
+	reapInterval = time.Second
+)
+
+// A Neighbor records the resources stolen by a single noisy neighbor.
+// Each neighbor holds a lease on the resources it stole. They are restored when
+// the neighbor is removed, or when its lease expires because the neighbor
+// stopped renewing it.
+type Neighbor struct {
+	ID      string `json:"id"`
+	Address string `json:"address"`
+	Resources
+	StartedAt time.Time `json:"started_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Label     string    `json:"label,omitempty"`
+	JobID     string    `json:"job_id,omitempty"`
+	QoS       QoS       `json:"qos"`
+}
+
+// neighborRegistry tracks the noisy neighbors of a SimulatedService.
+// Its lock is always taken before the service's state lock.
+type neighborRegistry struct {
+	sync.Mutex
+	neighbors map[string]*Neighbor
+}
+
+func newNeighborRegistry() *neighborRegistry {
+	return &neighborRegistry{neighbors: make(map[string]*Neighbor)}
+}
+
+// leaseTTL returns the lease duration for a TTL asked for in seconds.
+// Zero seconds means the neighbor didn't ask, so the default is used.
+func leaseTTL(seconds uint64) (time.Duration, error) {
+	if seconds == 0 {
+		return defaultLeaseTTL, nil
+	}
+	if seconds > uint64(maxLeaseTTL/time.Second) {
+		return 0, fmt.Errorf("the TTL cannot exceed %v seconds", uint64(maxLeaseTTL/time.Second))
+	}
+	return time.Duration(seconds) * time.Second, nil
+}
+
+// newNeighborID returns a random identifier for a neighbor.
+// It doesn't draw from the service's seeded generator, so IDs never repeat across seeded runs.
+func newNeighborID() string {
+	var buf = make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// AddNeighbor registers the neighbor, stealing its resources from this service
+// for the duration of the TTL. The neighbor's ID and timestamps are filled in.
+// It also returns the resources which were stolen before the neighbor arrived.
+// A neighbor which would push a stolen resource past the ceiling of its QoS class
+// is not registered, and an *AdmissionError is returned instead.
+func (service *SimulatedService) AddNeighbor(neighbor Neighbor, ttl time.Duration) (Neighbor, Resources, error) {
+	var now = time.Now()
+	neighbor.ID = newNeighborID()
+	neighbor.StartedAt = now
+	neighbor.ExpiresAt = now.Add(ttl)
+
+	service.neighbors.Lock()
+	defer service.neighbors.Unlock()
+	var previous, _, err = service.steal(neighbor.Resources, neighbor.QoS)
+	if rejected, ok := err.(*AdmissionError); ok {
+		service.events.Record(api.Event{
+			Type:      api.EventNeighborRejected,
+			Message:   fmt.Sprintf("Rejected a neighbor from %s: %v", neighbor.Address, rejected),
+			Admission: rejected.toV1(),
+		})
+	}
+	if err != nil {
+		return Neighbor{}, previous, err
+	}
+	service.neighbors.neighbors[neighbor.ID] = &neighbor
+	service.recordNeighbor(api.EventNeighborAdded, neighbor,
+		fmt.Sprintf("Neighbor %s added, stealing %s", neighbor.ID, neighbor.Resources.describe()))
+	return neighbor, previous, nil
+}
+
+// Neighbor returns the neighbor with the given ID.
+func (service *SimulatedService) Neighbor(id string) (Neighbor, bool) {
+	service.neighbors.Lock()
+	defer service.neighbors.Unlock()
+	var neighbor, ok = service.neighbors.neighbors[id]
+	if !ok {
+		return Neighbor{}, false
+	}
+	return *neighbor, true
+}
+
+// Neighbors returns every registered neighbor, oldest first.
+func (service *SimulatedService) Neighbors() []Neighbor {
+	service.neighbors.Lock()
+	var list = make([]Neighbor, 0, len(service.neighbors.neighbors))
+	for _, neighbor := range service.neighbors.neighbors {
+		list = append(list, *neighbor)
+	}
+	service.neighbors.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].StartedAt.Before(list[j].StartedAt)
+	})
+	return list
+}
+
+// RenewNeighbor pushes back the lease expiry of the neighbor with the given ID.
+// It returns false if no such neighbor exists, e.g. because its lease already expired.
+func (service *SimulatedService) RenewNeighbor(id string, ttl time.Duration) (Neighbor, bool) {
+	service.neighbors.Lock()
+	defer service.neighbors.Unlock()
+	var neighbor, ok = service.neighbors.neighbors[id]
+	if !ok {
+		return Neighbor{}, false
+	}
+	neighbor.ExpiresAt = time.Now().Add(ttl)
+	return *neighbor, true
+}
+
+// RemoveNeighbor restores the CPU held by the neighbor with the given ID.
+// It returns false if no such neighbor exists.
+func (service *SimulatedService) RemoveNeighbor(id string) (Neighbor, bool) {
+	service.neighbors.Lock()
+	defer service.neighbors.Unlock()
+	var neighbor, ok = service.neighbors.neighbors[id]
+	if !ok {
+		return Neighbor{}, false
+	}
+	service.removeLocked(neighbor, api.EventNeighborRemoved)
+	return *neighbor, true
+}
+
+// RemoveNeighborByCPU removes any one neighbor holding exactly the given CPU.
+// It supports older neighbors which remove themselves by CPU instead of by ID.
+func (service *SimulatedService) RemoveNeighborByCPU(cpu uint64) (Neighbor, bool) {
+	service.neighbors.Lock()
+	defer service.neighbors.Unlock()
+	for _, neighbor := range service.neighbors.neighbors {
+		if neighbor.CPU == cpu {
+			service.removeLocked(neighbor, api.EventNeighborRemoved)
+			return *neighbor, true
+		}
+	}
+	return Neighbor{}, false
+}
+
+// removeLocked unregisters the neighbor and restores its resources, recording
+// an event of the given type. The caller must hold the registry's lock.
+func (service *SimulatedService) removeLocked(neighbor *Neighbor, eventType string) {
+	delete(service.neighbors.neighbors, neighbor.ID)
+	service.restore(neighbor.Resources)
+	var verb = "removed"
+	if eventType == api.EventNeighborExpired {
+		verb = "expired"
+	}
+	service.recordNeighbor(eventType, *neighbor,
+		fmt.Sprintf("Neighbor %s %s, restoring %s", neighbor.ID, verb, neighbor.Resources.describe()))
+}
+
+// reapNeighbors periodically removes any neighbor which has stopped renewing its lease.
+func (service *SimulatedService) reapNeighbors() {
+	var ticker = time.NewTicker(reapInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			service.reapExpired(now)
+		case <-service.closed:
+			return
+		}
+	}
+}
+
+// reapExpired removes every neighbor whose lease expired before the given time.
+func (service *SimulatedService) reapExpired(now time.Time) {
+	service.neighbors.Lock()
+	defer service.neighbors.Unlock()
+	for _, neighbor := range service.neighbors.neighbors {
+		if now.After(neighbor.ExpiresAt) {
+			service.removeLocked(neighbor, api.EventNeighborExpired)
+			service.metrics.ObserveExpiration()
+		}
+	}
+}
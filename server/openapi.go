@@ -0,0 +1,905 @@
+package main
+
+import (
+	"net/http"
+)
+
+// openAPIPath is where the OpenAPI document describing every route is served.
+const openAPIPath = "/openapi.json"
+
+func (service *SimulatedService) handleOpenAPI(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "Method not allowed.", http.StatusMethodNotAllowed)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write([]byte(openAPIDocument))
+}
+
+// openAPIDocument is the OpenAPI 3 description of every route.
+// TestOpenAPI checks that the handlers and this document still agree.
+const openAPIDocument = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "Simulated service",
+    "description": "A service whose throughput, latency and liveness degrade as load rises and noisy neighbors steal resources from it. Routes under /v1 take and return JSON, and report every error in the same envelope. The older routes still work, but are deprecated. Any method which isn't documented for a route is answered with 405; under /v1, with an Allow header and the error envelope.",
+    "version": "1"
+  },
+  "paths": {
+    "/v1/health": {
+      "get": {
+        "operationId": "getHealth",
+        "summary": "Whether the service is alive under the load.",
+        "parameters": [{"$ref": "#/components/parameters/load"}],
+        "responses": {
+          "200": {"description": "The service is alive.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Health"}}}},
+          "503": {"description": "The service is dead.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Health"}}}},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/ready": {
+      "get": {
+        "operationId": "getReady",
+        "summary": "Whether the service should receive more traffic under the load.",
+        "parameters": [{"$ref": "#/components/parameters/load"}],
+        "responses": {
+          "200": {"description": "The service is ready.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Readiness"}}}},
+          "503": {"description": "The service is past its soft limit, dying or draining.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Readiness"}}}},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/load": {
+      "get": {
+        "operationId": "getLoad",
+        "summary": "The load held by the service.",
+        "responses": {
+          "200": {"$ref": "#/components/responses/Load"}
+        }
+      },
+      "post": {
+        "operationId": "setLoad",
+        "summary": "Set the load, overriding the load measured from requests.",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/SetLoadRequest"}}}},
+        "responses": {
+          "200": {"$ref": "#/components/responses/Load"},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "delete": {
+        "operationId": "clearLoad",
+        "summary": "Go back to measuring the load from the requests received in the last second.",
+        "responses": {
+          "200": {"$ref": "#/components/responses/Load"}
+        }
+      }
+    },
+    "/v1/throughput": {
+      "get": {
+        "operationId": "getThroughput",
+        "summary": "Requests completed per second under the load.",
+        "parameters": [{"$ref": "#/components/parameters/load"}],
+        "responses": {
+          "200": {"description": "The throughput. A dead service completes no requests.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Throughput"}}}},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/throughput/explain": {
+      "get": {
+        "operationId": "explainThroughput",
+        "summary": "Why the throughput under the load is what it is.",
+        "description": "Returns the inputs to the throughput, the regime the load fell in, and the intermediate values behind the result. In the degraded regime of the step model, the draw is the one behind the last throughput served under the same load, capacity and model. If none was served, the call draws a fresh sample from a generator of its own. Either way, explaining does not change the throughputs of a seeded service.",
+        "parameters": [{"$ref": "#/components/parameters/load"}],
+        "responses": {
+          "200": {"description": "The explanation.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Explanation"}}}},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/work": {
+      "get": {
+        "operationId": "getWork",
+        "summary": "Burn a request's worth of CPU, like POST /v1/work, for load generators which only send GET.",
+        "responses": {
+          "200": {"description": "The work was done.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/WorkResult"}}}},
+          "404": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "post": {
+        "operationId": "doWork",
+        "summary": "Burn a request's worth of CPU on the work pool.",
+        "description": "Only served in real mode, and 404 otherwise. The request waits for a worker, and is 503 if the queue is already full, or if it's abandoned before the work is done.",
+        "responses": {
+          "200": {"description": "The work was done.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/WorkResult"}}}},
+          "404": {"$ref": "#/components/responses/Error"},
+          "503": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/latency": {
+      "get": {
+        "operationId": "getLatency",
+        "summary": "Latency percentiles under the load.",
+        "parameters": [{"$ref": "#/components/parameters/load"}],
+        "responses": {
+          "200": {"description": "The percentiles, in milliseconds.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Latency"}}}},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/limits": {
+      "get": {
+        "operationId": "getLimits",
+        "summary": "The capacity limits, before and after noisy neighbors are accounted for.",
+        "responses": {
+          "200": {"$ref": "#/components/responses/Limits"}
+        }
+      },
+      "post": {
+        "operationId": "updateLimits",
+        "summary": "Change the capacity limits. Limits left out keep their value.",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/LimitsUpdate"}}}},
+        "responses": {
+          "200": {"$ref": "#/components/responses/Limits"},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/neighbors": {
+      "get": {
+        "operationId": "listNeighbors",
+        "summary": "Every noisy neighbor holding a lease.",
+        "responses": {
+          "200": {"description": "The neighbors, oldest first.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/NeighborList"}}}}
+        }
+      },
+      "post": {
+        "operationId": "addNeighbor",
+        "summary": "Steal resources, held by a lease until it's removed or expires.",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/NeighborRequest"}}}},
+        "responses": {
+          "201": {
+            "description": "The neighbor was admitted.",
+            "headers": {"Location": {"description": "The path of the new neighbor.", "schema": {"type": "string"}}},
+            "content": {"application/json": {"schema": {"$ref": "#/components/schemas/NeighborAdded"}}}
+          },
+          "400": {"$ref": "#/components/responses/Error"},
+          "409": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/neighbors/{id}": {
+      "parameters": [{"$ref": "#/components/parameters/neighborID"}],
+      "get": {
+        "operationId": "getNeighbor",
+        "summary": "Describe a noisy neighbor.",
+        "responses": {
+          "200": {"description": "The neighbor.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Neighbor"}}}},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "delete": {
+        "operationId": "removeNeighbor",
+        "summary": "Release a lease, restoring the resources it held.",
+        "responses": {
+          "200": {"description": "The removed neighbor.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/NeighborRemoved"}}}},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/neighbors/{id}/renew": {
+      "parameters": [{"$ref": "#/components/parameters/neighborID"}],
+      "post": {
+        "operationId": "renewNeighbor",
+        "summary": "Extend a lease.",
+        "requestBody": {"content": {"application/json": {"schema": {"$ref": "#/components/schemas/RenewRequest"}}}},
+        "responses": {
+          "200": {"description": "The neighbor, with its new expiry.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Neighbor"}}}},
+          "400": {"$ref": "#/components/responses/Error"},
+          "404": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/events": {
+      "get": {
+        "operationId": "listEvents",
+        "summary": "The history of state changes, oldest first.",
+        "description": "Neighbor adds, rejections, removals and expiries, limit changes, config reloads, load changes, deaths and recoveries are recorded. Only the most recent events are kept.",
+        "parameters": [
+          {"name": "type", "in": "query", "description": "Comma-separated event types to select. Defaults to every type.", "schema": {"type": "string"}},
+          {"name": "since", "in": "query", "description": "Select the events recorded at or after this RFC 3339 time.", "schema": {"type": "string", "format": "date-time"}},
+          {"name": "until", "in": "query", "description": "Select the events recorded at or before this RFC 3339 time.", "schema": {"type": "string", "format": "date-time"}},
+          {"name": "after", "in": "query", "description": "Select the events with a greater ID, to fetch the next page.", "schema": {"type": "integer", "minimum": 0}},
+          {"name": "limit", "in": "query", "description": "The most events on a page, from 1 to 1000. Defaults to 100.", "schema": {"type": "integer", "minimum": 1}}
+        ],
+        "responses": {
+          "200": {"description": "A page of events.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/EventPage"}}}},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/history": {
+      "get": {
+        "operationId": "getHistory",
+        "summary": "The throughput, available CPU and load, sampled every second and averaged over the resolution.",
+        "description": "Ten minutes are kept at 1s, two hours at 10s, and a day at 1m. The last point may still be filling up.",
+        "parameters": [
+          {"name": "resolution", "in": "query", "description": "The span of each point: 1s, 10s or 1m. Defaults to 1s.", "schema": {"type": "string"}},
+          {"name": "since", "in": "query", "description": "Select the points starting at or after this RFC 3339 time.", "schema": {"type": "string", "format": "date-time"}},
+          {"name": "until", "in": "query", "description": "Select the points starting at or before this RFC 3339 time.", "schema": {"type": "string", "format": "date-time"}}
+        ],
+        "responses": {
+          "200": {"description": "The points, oldest first.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/History"}}}},
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/v1/stream": {
+      "get": {
+        "operationId": "stream",
+        "summary": "Server-Sent Events describing the service as it changes.",
+        "description": "A state event is sent straight away, and whenever the limits, stolen resources, load or liveness change. A throughput event is sent every interval. The stream ends a little before the server's write timeout, after which subscribers should resubscribe; a fresh state event brings them up to date.",
+        "parameters": [
+          {"name": "interval", "in": "query", "description": "How often to send a throughput sample, as a Go duration of at least 100ms. Defaults to 1s.", "schema": {"type": "string"}}
+        ],
+        "responses": {
+          "200": {
+            "description": "The stream. The x-events extension gives the schema of the data of each named event.",
+            "content": {
+              "text/event-stream": {
+                "schema": {"type": "string"},
+                "x-events": {
+                  "state": {"$ref": "#/components/schemas/State"},
+                  "throughput": {"$ref": "#/components/schemas/ThroughputSample"}
+                }
+              }
+            }
+          },
+          "400": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/metrics": {
+      "get": {
+        "operationId": "getPrometheusMetrics",
+        "summary": "The state of the service in the Prometheus text exposition format.",
+        "parameters": [{"$ref": "#/components/parameters/load"}],
+        "responses": {
+          "200": {"description": "The metrics.", "content": {"text/plain": {"schema": {"type": "string"}}}},
+          "400": {"$ref": "#/components/responses/LegacyError"}
+        }
+      }
+    },
+    "/openapi.json": {
+      "get": {
+        "operationId": "getOpenAPI",
+        "summary": "This document.",
+        "responses": {
+          "200": {"description": "The OpenAPI document.", "content": {"application/json": {"schema": {"type": "object"}}}}
+        }
+      }
+    },
+    "/healthz": {
+      "get": {
+        "operationId": "legacyHealth",
+        "deprecated": true,
+        "summary": "Replaced by /v1/health. Responds with 200 even when dead, unless the server runs with -strict-health.",
+        "parameters": [{"$ref": "#/components/parameters/load"}],
+        "responses": {
+          "200": {"description": "The status, or a parse error.", "content": {
+            "application/json": {"schema": {"$ref": "#/components/schemas/HealthCheckResponse"}},
+            "text/plain": {"schema": {"type": "string"}}
+          }},
+          "503": {"description": "The service is dead, in strict mode.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/HealthCheckResponse"}}}},
+          "400": {"$ref": "#/components/responses/LegacyError"}
+        }
+      }
+    },
+    "/readyz": {
+      "get": {
+        "operationId": "legacyReady",
+        "deprecated": true,
+        "summary": "Replaced by /v1/ready.",
+        "parameters": [{"$ref": "#/components/parameters/load"}],
+        "responses": {
+          "200": {"description": "The service is ready.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Readiness"}}}},
+          "503": {"description": "The service is not ready.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Readiness"}}}},
+          "400": {"$ref": "#/components/responses/LegacyError"}
+        }
+      }
+    },
+    "/load": {
+      "get": {
+        "operationId": "legacyGetLoad",
+        "deprecated": true,
+        "summary": "Replaced by GET /v1/load.",
+        "responses": {"200": {"$ref": "#/components/responses/Load"}}
+      },
+      "post": {
+        "operationId": "legacySetLoad",
+        "deprecated": true,
+        "summary": "Replaced by POST /v1/load.",
+        "parameters": [{"$ref": "#/components/parameters/requiredLoad"}],
+        "responses": {
+          "200": {"$ref": "#/components/responses/Load"},
+          "400": {"$ref": "#/components/responses/LegacyError"}
+        }
+      },
+      "put": {
+        "operationId": "legacyPutLoad",
+        "deprecated": true,
+        "summary": "Replaced by POST /v1/load.",
+        "parameters": [{"$ref": "#/components/parameters/requiredLoad"}],
+        "responses": {
+          "200": {"$ref": "#/components/responses/Load"},
+          "400": {"$ref": "#/components/responses/LegacyError"}
+        }
+      },
+      "delete": {
+        "operationId": "legacyClearLoad",
+        "deprecated": true,
+        "summary": "Replaced by DELETE /v1/load.",
+        "responses": {"200": {"$ref": "#/components/responses/Load"}}
+      }
+    },
+    "/metrics/throughput": {
+      "get": {
+        "operationId": "legacyGetThroughput",
+        "deprecated": true,
+        "summary": "Replaced by GET /v1/throughput.",
+        "parameters": [{"$ref": "#/components/parameters/load"}],
+        "responses": {
+          "200": {"description": "The throughput, or a parse error.", "content": {
+            "application/json": {"schema": {"$ref": "#/components/schemas/ThroughputGETResponse"}},
+            "text/plain": {"schema": {"type": "string"}}
+          }}
+        }
+      },
+      "post": {
+        "operationId": "legacyUpdateThroughput",
+        "deprecated": true,
+        "summary": "Replaced by POST /v1/limits.",
+        "parameters": [
+          {"name": "throughput", "in": "query", "schema": {"type": "integer", "minimum": 1}},
+          {"name": "soft_limit", "in": "query", "schema": {"type": "integer", "minimum": 0}},
+          {"name": "hard_limit", "in": "query", "schema": {"type": "integer", "minimum": 0}}
+        ],
+        "responses": {
+          "200": {"description": "The new limits.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ThroughputPOSTResponse"}}}},
+          "400": {"$ref": "#/components/responses/LegacyError"}
+        }
+      }
+    },
+    "/metrics/latency": {
+      "get": {
+        "operationId": "legacyGetLatency",
+        "deprecated": true,
+        "summary": "Replaced by GET /v1/latency.",
+        "parameters": [{"$ref": "#/components/parameters/load"}],
+        "responses": {
+          "200": {"description": "The percentiles, or a parse error.", "content": {
+            "application/json": {"schema": {"$ref": "#/components/schemas/Latency"}},
+            "text/plain": {"schema": {"type": "string"}}
+          }}
+        }
+      }
+    },
+    "/neighbors/add": {
+      "get": {
+        "operationId": "legacyAddNeighbor",
+        "deprecated": true,
+        "summary": "Replaced by POST /v1/neighbors. At least one resource must be given.",
+        "parameters": [
+          {"name": "cpu", "in": "query", "schema": {"type": "integer", "minimum": 0}},
+          {"name": "memory_bandwidth", "in": "query", "schema": {"type": "integer", "minimum": 0}},
+          {"name": "disk_io", "in": "query", "schema": {"type": "integer", "minimum": 0}},
+          {"name": "network", "in": "query", "schema": {"type": "integer", "minimum": 0}},
+          {"$ref": "#/components/parameters/ttl"},
+          {"name": "label", "in": "query", "schema": {"type": "string"}},
+          {"name": "job_id", "in": "query", "schema": {"type": "string"}},
+          {"name": "qos", "in": "query", "schema": {"$ref": "#/components/schemas/QoS"}}
+        ],
+        "responses": {
+          "200": {"description": "The lease, or a parse error.", "content": {
+            "application/json": {"schema": {"$ref": "#/components/schemas/NeighborAddResponse"}},
+            "text/plain": {"schema": {"type": "string"}}
+          }},
+          "400": {"$ref": "#/components/responses/LegacyError"},
+          "409": {"description": "The neighbor wasn't admitted.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/AdmissionRejectedResponse"}}}}
+        }
+      }
+    },
+    "/neighbors/renew": {
+      "get": {
+        "operationId": "legacyRenewNeighbor",
+        "deprecated": true,
+        "summary": "Replaced by POST /v1/neighbors/{id}/renew.",
+        "parameters": [
+          {"name": "id", "in": "query", "required": true, "schema": {"type": "string"}},
+          {"$ref": "#/components/parameters/ttl"}
+        ],
+        "responses": {
+          "200": {"description": "The renewed lease, or a parse error.", "content": {
+            "application/json": {"schema": {"$ref": "#/components/schemas/NeighborRenewResponse"}},
+            "text/plain": {"schema": {"type": "string"}}
+          }},
+          "404": {"$ref": "#/components/responses/LegacyError"}
+        }
+      }
+    },
+    "/neighbors/remove": {
+      "get": {
+        "operationId": "legacyRemoveNeighbor",
+        "deprecated": true,
+        "summary": "Replaced by DELETE /v1/neighbors/{id}. Without an id, any one neighbor holding exactly the given CPU is removed.",
+        "parameters": [
+          {"name": "id", "in": "query", "schema": {"type": "string"}},
+          {"name": "cpu", "in": "query", "schema": {"type": "integer", "minimum": 0}}
+        ],
+        "responses": {
+          "200": {"description": "The removed lease, or a parse error.", "content": {
+            "application/json": {"schema": {"$ref": "#/components/schemas/NeighborRemoveResponse"}},
+            "text/plain": {"schema": {"type": "string"}}
+          }},
+          "404": {"$ref": "#/components/responses/LegacyError"}
+        }
+      }
+    },
+    "/neighbors": {
+      "get": {
+        "operationId": "legacyListNeighbors",
+        "deprecated": true,
+        "summary": "Replaced by GET /v1/neighbors.",
+        "responses": {
+          "200": {"description": "The neighbors, oldest first.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/NeighborListResponse"}}}}
+        }
+      }
+    },
+    "/neighbors/{id}": {
+      "parameters": [{"$ref": "#/components/parameters/neighborID"}],
+      "get": {
+        "operationId": "legacyGetNeighbor",
+        "deprecated": true,
+        "summary": "Replaced by GET /v1/neighbors/{id}.",
+        "responses": {
+          "200": {"description": "The neighbor.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Neighbor"}}}},
+          "404": {"$ref": "#/components/responses/LegacyError"}
+        }
+      },
+      "delete": {
+        "operationId": "legacyDeleteNeighbor",
+        "deprecated": true,
+        "summary": "Replaced by DELETE /v1/neighbors/{id}.",
+        "responses": {
+          "200": {"description": "The removed lease.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/NeighborRemoveResponse"}}}},
+          "404": {"$ref": "#/components/responses/LegacyError"}
+        }
+      }
+    }
+  },
+  "components": {
+    "parameters": {
+      "load": {"name": "load", "in": "query", "description": "Requests per second offered to the service. Defaults to the load held by the service.", "schema": {"type": "integer", "minimum": 0}},
+      "requiredLoad": {"name": "load", "in": "query", "required": true, "description": "Requests per second offered to the service.", "schema": {"type": "integer", "minimum": 0}},
+      "ttl": {"name": "ttl", "in": "query", "description": "Seconds until the lease expires unless renewed. Defaults to 30.", "schema": {"type": "integer", "minimum": 1}},
+      "neighborID": {"name": "id", "in": "path", "required": true, "schema": {"type": "string"}}
+    },
+    "responses": {
+      "Error": {"description": "The request failed.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/ErrorResponse"}}}},
+      "LegacyError": {"description": "The request failed.", "content": {"text/plain": {"schema": {"type": "string"}}}},
+      "Load": {"description": "The load held by the service.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Load"}}}},
+      "Limits": {"description": "The capacity limits.", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Limits"}}}}
+    },
+    "schemas": {
+      "ErrorResponse": {
+        "type": "object",
+        "required": ["error"],
+        "additionalProperties": false,
+        "properties": {"error": {"$ref": "#/components/schemas/Error"}}
+      },
+      "Error": {
+        "type": "object",
+        "required": ["code", "message"],
+        "additionalProperties": false,
+        "properties": {
+          "code": {"type": "string", "enum": ["invalid_argument", "not_found", "method_not_allowed", "admission_rejected", "unavailable", "internal"]},
+          "message": {"type": "string"},
+          "admission": {"$ref": "#/components/schemas/AdmissionRejection"}
+        }
+      },
+      "AdmissionRejection": {
+        "type": "object",
+        "required": ["qos", "resource", "requested", "stolen", "ceiling"],
+        "additionalProperties": false,
+        "properties": {
+          "qos": {"$ref": "#/components/schemas/QoS"},
+          "resource": {"$ref": "#/components/schemas/Resource"},
+          "requested": {"type": "integer", "minimum": 0},
+          "stolen": {"type": "integer", "minimum": 0},
+          "ceiling": {"type": "integer", "minimum": 0}
+        }
+      },
+      "QoS": {"type": "string", "enum": ["guaranteed", "burstable", "best-effort"]},
+      "Resource": {"type": "string", "enum": ["cpu", "memory_bandwidth", "disk_io", "network"]},
+      "Resources": {
+        "type": "object",
+        "description": "A percentage of each resource.",
+        "required": ["cpu", "memory_bandwidth", "disk_io", "network"],
+        "additionalProperties": false,
+        "properties": {
+          "cpu": {"type": "integer", "minimum": 0},
+          "memory_bandwidth": {"type": "integer", "minimum": 0},
+          "disk_io": {"type": "integer", "minimum": 0},
+          "network": {"type": "integer", "minimum": 0}
+        }
+      },
+      "Health": {
+        "type": "object",
+        "required": ["alive", "load", "available_cpu", "stolen"],
+        "additionalProperties": false,
+        "properties": {
+          "alive": {"type": "boolean"},
+          "load": {"type": "integer", "minimum": 0},
+          "available_cpu": {"type": "number", "minimum": 0, "description": "The percentage of CPU left to the service."},
+          "stolen": {"$ref": "#/components/schemas/Resources"}
+        }
+      },
+      "Readiness": {
+        "type": "object",
+        "required": ["ready", "load", "soft_limit"],
+        "additionalProperties": false,
+        "properties": {
+          "ready": {"type": "boolean"},
+          "load": {"type": "integer", "minimum": 0},
+          "soft_limit": {"type": "integer", "minimum": 0}
+        }
+      },
+      "Load": {
+        "type": "object",
+        "required": ["load", "source"],
+        "additionalProperties": false,
+        "properties": {
+          "load": {"type": "integer", "minimum": 0},
+          "source": {"type": "string", "enum": ["set", "measured"]}
+        }
+      },
+      "SetLoadRequest": {
+        "type": "object",
+        "required": ["load"],
+        "additionalProperties": false,
+        "properties": {"load": {"type": "integer", "minimum": 0}}
+      },
+      "Throughput": {
+        "type": "object",
+        "required": ["load", "throughput"],
+        "additionalProperties": false,
+        "properties": {
+          "load": {"type": "integer", "minimum": 0},
+          "throughput": {"type": "integer", "minimum": 0}
+        }
+      },
+      "Latency": {
+        "type": "object",
+        "required": ["load", "alive", "p50_ms", "p90_ms", "p99_ms", "p999_ms"],
+        "additionalProperties": false,
+        "properties": {
+          "load": {"type": "integer", "minimum": 0},
+          "alive": {"type": "boolean"},
+          "p50_ms": {"type": "number", "minimum": 0},
+          "p90_ms": {"type": "number", "minimum": 0},
+          "p99_ms": {"type": "number", "minimum": 0},
+          "p999_ms": {"type": "number", "minimum": 0}
+        }
+      },
+      "Limits": {
+        "type": "object",
+        "required": ["max_throughput", "soft_limit", "hard_limit", "available_throughput", "modified_soft_limit", "modified_hard_limit"],
+        "additionalProperties": false,
+        "properties": {
+          "max_throughput": {"type": "integer", "minimum": 1},
+          "soft_limit": {"type": "integer", "minimum": 0},
+          "hard_limit": {"type": "integer", "minimum": 0},
+          "available_throughput": {"type": "integer", "minimum": 0},
+          "modified_soft_limit": {"type": "integer", "minimum": 0},
+          "modified_hard_limit": {"type": "integer", "minimum": 0}
+        }
+      },
+      "LimitsUpdate": {
+        "type": "object",
+        "additionalProperties": false,
+        "properties": {
+          "max_throughput": {"type": "integer", "minimum": 1},
+          "soft_limit": {"type": "integer", "minimum": 0},
+          "hard_limit": {"type": "integer", "minimum": 0}
+        }
+      },
+      "Neighbor": {
+        "type": "object",
+        "required": ["id", "address", "cpu", "memory_bandwidth", "disk_io", "network", "started_at", "expires_at", "qos"],
+        "additionalProperties": false,
+        "properties": {
+          "id": {"type": "string"},
+          "address": {"type": "string"},
+          "cpu": {"type": "integer", "minimum": 0},
+          "memory_bandwidth": {"type": "integer", "minimum": 0},
+          "disk_io": {"type": "integer", "minimum": 0},
+          "network": {"type": "integer", "minimum": 0},
+          "started_at": {"type": "string", "format": "date-time"},
+          "expires_at": {"type": "string", "format": "date-time"},
+          "label": {"type": "string"},
+          "job_id": {"type": "string"},
+          "qos": {"$ref": "#/components/schemas/QoS"}
+        }
+      },
+      "NeighborRequest": {
+        "type": "object",
+        "description": "At least one resource must be stolen.",
+        "additionalProperties": false,
+        "properties": {
+          "cpu": {"type": "integer", "minimum": 0},
+          "memory_bandwidth": {"type": "integer", "minimum": 0},
+          "disk_io": {"type": "integer", "minimum": 0},
+          "network": {"type": "integer", "minimum": 0},
+          "ttl_seconds": {"type": "integer", "minimum": 0, "maximum": 86400, "description": "0 uses the default of 30 seconds. At most a day."},
+          "label": {"type": "string"},
+          "job_id": {"type": "string"},
+          "qos": {"$ref": "#/components/schemas/QoS"}
+        }
+      },
+      "RenewRequest": {
+        "type": "object",
+        "additionalProperties": false,
+        "properties": {
+          "ttl_seconds": {"type": "integer", "minimum": 0, "maximum": 86400, "description": "0 uses the default of 30 seconds. At most a day."}
+        }
+      },
+      "NeighborAdded": {
+        "type": "object",
+        "required": ["neighbor", "previous_stolen", "stolen"],
+        "additionalProperties": false,
+        "properties": {
+          "neighbor": {"$ref": "#/components/schemas/Neighbor"},
+          "previous_stolen": {"$ref": "#/components/schemas/Resources"},
+          "stolen": {"$ref": "#/components/schemas/Resources"}
+        }
+      },
+      "NeighborRemoved": {
+        "type": "object",
+        "required": ["neighbor", "stolen"],
+        "additionalProperties": false,
+        "properties": {
+          "neighbor": {"$ref": "#/components/schemas/Neighbor"},
+          "stolen": {"$ref": "#/components/schemas/Resources"}
+        }
+      },
+      "NeighborList": {
+        "type": "object",
+        "required": ["neighbors", "stolen"],
+        "additionalProperties": false,
+        "properties": {
+          "neighbors": {"type": "array", "items": {"$ref": "#/components/schemas/Neighbor"}},
+          "stolen": {"$ref": "#/components/schemas/Resources"}
+        }
+      },
+      "Event": {
+        "type": "object",
+        "required": ["id", "time", "type", "message"],
+        "additionalProperties": false,
+        "properties": {
+          "id": {"type": "integer", "minimum": 1},
+          "time": {"type": "string", "format": "date-time"},
+          "type": {"type": "string", "enum": ["neighbor_added", "neighbor_rejected", "neighbor_removed", "neighbor_expired", "limits_changed", "reconfigured", "load_set", "load_cleared", "alive", "dead", "dying", "draining"]},
+          "message": {"type": "string"},
+          "neighbor": {"$ref": "#/components/schemas/Neighbor"},
+          "admission": {"$ref": "#/components/schemas/AdmissionRejection"},
+          "limits": {"$ref": "#/components/schemas/Limits"},
+          "load": {"type": "integer", "minimum": 0}
+        }
+      },
+      "EventPage": {
+        "type": "object",
+        "required": ["events", "next_after"],
+        "additionalProperties": false,
+        "properties": {
+          "events": {"type": "array", "items": {"$ref": "#/components/schemas/Event"}},
+          "next_after": {"type": "integer", "minimum": 0, "description": "The after param of the next page, or 0 on the last page."}
+        }
+      },
+      "History": {
+        "type": "object",
+        "required": ["resolution", "points"],
+        "additionalProperties": false,
+        "properties": {
+          "resolution": {"type": "string", "enum": ["1s", "10s", "1m"]},
+          "points": {"type": "array", "items": {"$ref": "#/components/schemas/HistoryPoint"}}
+        }
+      },
+      "HistoryPoint": {
+        "type": "object",
+        "required": ["time", "throughput", "available_cpu", "load", "samples"],
+        "additionalProperties": false,
+        "properties": {
+          "time": {"type": "string", "format": "date-time", "description": "The start of the point's span."},
+          "throughput": {"type": "number", "minimum": 0},
+          "available_cpu": {"type": "number", "minimum": 0, "description": "The percentage of CPU left to the service."},
+          "load": {"type": "number", "minimum": 0},
+          "samples": {"type": "integer", "minimum": 1}
+        }
+      },
+      "State": {
+        "type": "object",
+        "required": ["time", "max_throughput", "soft_limit", "hard_limit", "available_throughput", "modified_soft_limit", "modified_hard_limit", "stolen", "neighbors", "load", "load_source", "alive", "ready", "dying", "draining"],
+        "additionalProperties": false,
+        "properties": {
+          "time": {"type": "string", "format": "date-time"},
+          "max_throughput": {"type": "integer", "minimum": 1},
+          "soft_limit": {"type": "integer", "minimum": 0},
+          "hard_limit": {"type": "integer", "minimum": 0},
+          "available_throughput": {"type": "integer", "minimum": 0},
+          "modified_soft_limit": {"type": "integer", "minimum": 0},
+          "modified_hard_limit": {"type": "integer", "minimum": 0},
+          "stolen": {"$ref": "#/components/schemas/Resources"},
+          "neighbors": {"type": "integer", "minimum": 0},
+          "load": {"type": "integer", "minimum": 0},
+          "load_source": {"type": "string", "enum": ["set", "measured"]},
+          "alive": {"type": "boolean"},
+          "ready": {"type": "boolean"},
+          "dying": {"type": "boolean"},
+          "draining": {"type": "boolean"}
+        }
+      },
+      "ThroughputSample": {
+        "type": "object",
+        "required": ["time", "load", "throughput", "available_cpu"],
+        "additionalProperties": false,
+        "properties": {
+          "time": {"type": "string", "format": "date-time"},
+          "load": {"type": "integer", "minimum": 0},
+          "throughput": {"type": "integer", "minimum": 0},
+          "available_cpu": {"type": "number", "minimum": 0, "description": "The percentage of CPU left to the service."}
+        }
+      },
+      "Explanation": {
+        "type": "object",
+        "required": ["load", "throughput", "model", "regime", "stolen", "available_cpu", "availability", "warm_up", "limits", "dying", "values"],
+        "additionalProperties": false,
+        "properties": {
+          "load": {"type": "integer", "minimum": 0},
+          "throughput": {"type": "integer", "minimum": 0},
+          "model": {"type": "string", "enum": ["step", "mm1", "mmc", "usl", "real"], "description": "The throughput model, or real when the throughput is measured from /v1/work."},
+          "regime": {"type": "string", "enum": ["healthy", "saturated", "degraded", "idle", "dropping", "scaling", "retrograde", "dead", "measured"], "description": "Where the load fell on the model's curve. The step model is healthy, saturated or degraded; mm1 and mmc are idle, healthy or dropping; usl is idle, scaling or retrograde. Any model is dead past the modified hard limit, or while dying. A real service is always measured."},
+          "stolen": {"$ref": "#/components/schemas/Resources"},
+          "available_cpu": {"type": "number", "minimum": 0, "description": "The percentage of CPU left to the service."},
+          "availability": {"type": "number", "minimum": 0, "description": "The percentage of its capacity the service keeps, given its sensitivity to the stolen resources."},
+          "warm_up": {"type": "number", "minimum": 0, "description": "The percentage of its capacity the service has warmed up to."},
+          "measured_cpu": {"$ref": "#/components/schemas/MeasuredCPU"},
+          "limits": {"$ref": "#/components/schemas/Limits"},
+          "dying": {"type": "boolean"},
+          "values": {"type": "object", "additionalProperties": {"type": "number"}, "description": "The model's intermediate values, by name."},
+          "draw": {"$ref": "#/components/schemas/Draw"}
+        }
+      },
+      "MeasuredCPU": {
+        "type": "object",
+        "required": ["available", "simulated", "weight", "steal", "iowait", "throttled", "quota_cpus"],
+        "additionalProperties": false,
+        "description": "The CPU contention measured on the host over the last second, set when the cpu source is measured or blended. Every value but weight and quota_cpus is a percentage.",
+        "properties": {
+          "available": {"type": "number", "minimum": 0, "description": "The CPU left once the cgroup's quota, as a share of the CPUs the service sees, and the measured contention are accounted for."},
+          "simulated": {"type": "number", "minimum": 0, "description": "The CPU left by the simulated noisy neighbors."},
+          "weight": {"type": "number", "minimum": 0, "description": "How much the measurement counts towards available_cpu, from 0 to 1."},
+          "steal": {"type": "number", "minimum": 0, "description": "The share of the host's CPU time stolen by the hypervisor."},
+          "iowait": {"type": "number", "minimum": 0, "description": "The share of the host's CPU time spent waiting on I/O."},
+          "throttled": {"type": "number", "minimum": 0, "description": "The share of the cgroup's quota periods in which the service was throttled."},
+          "quota_cpus": {"type": "number", "minimum": 0, "description": "The cgroup's cpu.max quota, in CPUs. 0 means unlimited."}
+        }
+      },
+      "Draw": {
+        "type": "object",
+        "required": ["min", "max", "value"],
+        "additionalProperties": false,
+        "description": "A throughput drawn at random from min up to, but not including, max.",
+        "properties": {
+          "min": {"type": "integer", "minimum": 0},
+          "max": {"type": "integer", "minimum": 0},
+          "value": {"type": "integer", "minimum": 0}
+        }
+      },
+      "WorkResult": {
+        "type": "object",
+        "required": ["cpu_ms", "queued_ms", "worked_ms"],
+        "additionalProperties": false,
+        "properties": {
+          "cpu_ms": {"type": "number", "minimum": 0, "description": "The CPU time the work takes on an idle machine."},
+          "queued_ms": {"type": "number", "minimum": 0, "description": "How long the request waited for a worker."},
+          "worked_ms": {"type": "number", "minimum": 0, "description": "How long the work took, which is longer than cpu_ms when something else competes for the CPU."}
+        }
+      },
+      "HealthCheckResponse": {
+        "type": "object",
+        "required": ["alive", "avaiable_cpu", "stolen"],
+        "additionalProperties": false,
+        "properties": {
+          "alive": {"type": "boolean"},
+          "avaiable_cpu": {"type": "string", "description": "The percentage of CPU left, as a string. The key is misspelt."},
+          "stolen": {"$ref": "#/components/schemas/Resources"}
+        }
+      },
+      "ThroughputGETResponse": {
+        "type": "object",
+        "required": ["throughput"],
+        "additionalProperties": false,
+        "properties": {"throughput": {"type": "integer", "minimum": 0}}
+      },
+      "ThroughputPOSTResponse": {
+        "type": "object",
+        "required": ["throughput", "soft_limit", "hard_limit"],
+        "additionalProperties": false,
+        "properties": {
+          "throughput": {"type": "integer", "minimum": 1},
+          "soft_limit": {"type": "integer", "minimum": 0},
+          "hard_limit": {"type": "integer", "minimum": 0}
+        }
+      },
+      "NeighborAddResponse": {
+        "type": "object",
+        "required": ["PreviousStolenCPU", "StolenCPU", "LeaseID", "ExpiresAt", "StolenResources"],
+        "additionalProperties": false,
+        "properties": {
+          "PreviousStolenCPU": {"type": "integer", "minimum": 0},
+          "StolenCPU": {"type": "integer", "minimum": 0},
+          "LeaseID": {"type": "string"},
+          "ExpiresAt": {"type": "string", "format": "date-time"},
+          "StolenResources": {"$ref": "#/components/schemas/Resources"}
+        }
+      },
+      "AdmissionRejectedResponse": {
+        "type": "object",
+        "required": ["error", "qos", "resource", "requested", "stolen", "ceiling"],
+        "additionalProperties": false,
+        "properties": {
+          "error": {"type": "string"},
+          "qos": {"$ref": "#/components/schemas/QoS"},
+          "resource": {"$ref": "#/components/schemas/Resource"},
+          "requested": {"type": "integer", "minimum": 0},
+          "stolen": {"type": "integer", "minimum": 0},
+          "ceiling": {"type": "integer", "minimum": 0}
+        }
+      },
+      "NeighborRenewResponse": {
+        "type": "object",
+        "required": ["LeaseID", "ExpiresAt"],
+        "additionalProperties": false,
+        "properties": {
+          "LeaseID": {"type": "string"},
+          "ExpiresAt": {"type": "string", "format": "date-time"}
+        }
+      },
+      "NeighborRemoveResponse": {
+        "type": "object",
+        "required": ["StolenCPU", "RestoredCPU", "StolenResources", "RestoredResources"],
+        "additionalProperties": false,
+        "properties": {
+          "StolenCPU": {"type": "integer", "minimum": 0},
+          "RestoredCPU": {"type": "integer", "minimum": 0},
+          "StolenResources": {"$ref": "#/components/schemas/Resources"},
+          "RestoredResources": {"$ref": "#/components/schemas/Resources"}
+        }
+      },
+      "NeighborListResponse": {
+        "type": "object",
+        "required": ["neighbors", "stolen_cpu", "stolen"],
+        "additionalProperties": false,
+        "properties": {
+          "neighbors": {"type": "array", "items": {"$ref": "#/components/schemas/Neighbor"}},
+          "stolen_cpu": {"type": "integer", "minimum": 0},
+          "stolen": {"$ref": "#/components/schemas/Resources"}
+        }
+      }
+    }
+  }
+}
+`
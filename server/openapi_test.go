@@ -0,0 +1,448 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
+)
+
+// leasePlaceholder is replaced by the ID of the last lease handed out
+// while checking the OpenAPI document.
+const leasePlaceholder = "{lease}"
+
+// An exercise is a request sent to the handlers by TestOpenAPI.
+type exercise struct {
+	method, target, body string
+	// strictHealth turns on the service's strict health mode for this request.
+	strictHealth bool
+	// timeout cancels the request after the duration, for streams which don't end on their own.
+	timeout time.Duration
+	// real puts the service in real mode for this request.
+	real bool
+}
+
+// exercises calls every documented operation, and reaches each documented
+// status code which can be reached without waiting on the clock.
+var exercises = []exercise{
+	{method: "GET", target: "/openapi.json"},
+	{method: "PUT", target: "/openapi.json"},
+	{method: "GET", target: "/metrics?load=10"},
+	{method: "GET", target: "/metrics?load=x"},
+
+	{method: "GET", target: "/v1/health?load=10"},
+	{method: "GET", target: "/v1/health?load=1000000"},
+	{method: "GET", target: "/v1/health?load=x"},
+	{method: "POST", target: "/v1/health"},
+	{method: "GET", target: "/v1/ready?load=10"},
+	{method: "GET", target: "/v1/ready?load=1000000"},
+	{method: "GET", target: "/v1/ready?load=x"},
+	{method: "POST", target: "/v1/ready"},
+	{method: "GET", target: "/v1/load"},
+	{method: "POST", target: "/v1/load", body: `{"load": 5}`},
+	{method: "POST", target: "/v1/load", body: `{"lod": 5}`},
+	{method: "POST", target: "/v1/load"},
+	{method: "POST", target: "/v1/load", body: `{}`},
+	{method: "DELETE", target: "/v1/load"},
+	{method: "PUT", target: "/v1/load"},
+	{method: "GET", target: "/v1/throughput?load=10"},
+	{method: "GET", target: "/v1/throughput?load=x"},
+	{method: "POST", target: "/v1/throughput"},
+	{method: "GET", target: "/v1/throughput/explain?load=10"},
+	{method: "GET", target: "/v1/throughput/explain?load=1800"},
+	{method: "GET", target: "/v1/throughput/explain?load=1000000"},
+	{method: "GET", target: "/v1/throughput/explain?load=x"},
+	{method: "POST", target: "/v1/throughput/explain"},
+	{method: "POST", target: "/v1/work", real: true},
+	{method: "GET", target: "/v1/work", real: true},
+	{method: "GET", target: "/v1/throughput/explain", real: true},
+	{method: "POST", target: "/v1/work"},
+	{method: "PUT", target: "/v1/work", real: true},
+	{method: "GET", target: "/v1/latency?load=10"},
+	{method: "GET", target: "/v1/latency?load=x"},
+	{method: "POST", target: "/v1/latency"},
+	{method: "GET", target: "/v1/limits"},
+	{method: "POST", target: "/v1/limits", body: `{"soft_limit": 1600}`},
+	{method: "POST", target: "/v1/limits", body: `{"soft_limit": 99999}`},
+	{method: "PUT", target: "/v1/limits"},
+	{method: "POST", target: "/v1/neighbors", body: `{"cpu": 10, "label": "check", "job_id": "check"}`},
+	{method: "POST", target: "/v1/neighbors", body: `{"network": 90, "qos": "best-effort"}`},
+	{method: "POST", target: "/v1/neighbors", body: `{}`},
+	{method: "PUT", target: "/v1/neighbors"},
+	{method: "GET", target: "/v1/neighbors"},
+	{method: "GET", target: "/v1/neighbors/" + leasePlaceholder},
+	{method: "PUT", target: "/v1/neighbors/" + leasePlaceholder},
+	{method: "POST", target: "/v1/neighbors/" + leasePlaceholder + "/renew", body: `{"ttl_seconds": 60}`},
+	{method: "POST", target: "/v1/neighbors/" + leasePlaceholder + "/renew", body: `{"ttl": 60}`},
+	{method: "GET", target: "/v1/neighbors/" + leasePlaceholder + "/renew"},
+	{method: "DELETE", target: "/v1/neighbors/" + leasePlaceholder},
+	{method: "DELETE", target: "/v1/neighbors/" + leasePlaceholder},
+	{method: "GET", target: "/v1/neighbors/" + leasePlaceholder},
+	{method: "POST", target: "/v1/neighbors/" + leasePlaceholder + "/renew"},
+	{method: "GET", target: "/v1/events"},
+	{method: "GET", target: "/v1/events?type=neighbor_added,neighbor_removed&limit=1"},
+	{method: "GET", target: "/v1/events?after=3&since=2000-01-01T00:00:00Z&until=2100-01-01T00:00:00Z"},
+	{method: "GET", target: "/v1/events?type=x"},
+	{method: "GET", target: "/v1/events?limit=0"},
+	{method: "GET", target: "/v1/events?since=yesterday"},
+	{method: "DELETE", target: "/v1/events"},
+	{method: "GET", target: "/v1/history"},
+	{method: "GET", target: "/v1/history?resolution=1m&since=2000-01-01T00:00:00Z&until=2100-01-01T00:00:00Z"},
+	{method: "GET", target: "/v1/history?resolution=5s"},
+	{method: "GET", target: "/v1/history?until=x"},
+	{method: "POST", target: "/v1/history"},
+	{method: "GET", target: "/v1/stream?interval=100ms", timeout: 350 * time.Millisecond},
+	{method: "GET", target: "/v1/stream?interval=1ms"},
+	{method: "GET", target: "/v1/stream?interval=x"},
+	{method: "POST", target: "/v1/stream"},
+
+	{method: "GET", target: "/healthz?load=10"},
+	{method: "GET", target: "/healthz?load=x"},
+	{method: "GET", target: "/healthz?load=1000000", strictHealth: true},
+	{method: "GET", target: "/healthz?load=x", strictHealth: true},
+	{method: "GET", target: "/readyz?load=10"},
+	{method: "GET", target: "/readyz?load=1000000"},
+	{method: "GET", target: "/readyz?load=x"},
+	{method: "GET", target: "/load"},
+	{method: "POST", target: "/load?load=5"},
+	{method: "POST", target: "/load?load=x"},
+	{method: "PUT", target: "/load?load=5"},
+	{method: "PUT", target: "/load?load=x"},
+	{method: "DELETE", target: "/load"},
+	{method: "GET", target: "/metrics/throughput?load=10"},
+	{method: "GET", target: "/metrics/throughput?load=x"},
+	{method: "POST", target: "/metrics/throughput?soft_limit=1500"},
+	{method: "POST", target: "/metrics/throughput?soft_limit=x"},
+	{method: "GET", target: "/metrics/latency?load=10"},
+	{method: "GET", target: "/metrics/latency?load=x"},
+	{method: "GET", target: "/neighbors/add?cpu=5&label=check&job_id=check"},
+	{method: "GET", target: "/neighbors/add?cpu=x"},
+	{method: "GET", target: "/neighbors/add?network=90&qos=best-effort"},
+	{method: "GET", target: "/neighbors/add?cpu=1&qos=x"},
+	{method: "GET", target: "/neighbors/renew?id=" + leasePlaceholder},
+	{method: "GET", target: "/neighbors/renew?id=" + leasePlaceholder + "&ttl=x"},
+	{method: "GET", target: "/neighbors/renew?id=x"},
+	{method: "GET", target: "/neighbors"},
+	{method: "GET", target: "/neighbors/" + leasePlaceholder},
+	{method: "PUT", target: "/neighbors/" + leasePlaceholder},
+	{method: "GET", target: "/neighbors/remove?id=" + leasePlaceholder},
+	{method: "GET", target: "/neighbors/remove?id=" + leasePlaceholder},
+	{method: "GET", target: "/neighbors/remove?cpu=x"},
+	{method: "GET", target: "/neighbors/add?disk_io=5"},
+	{method: "DELETE", target: "/neighbors/" + leasePlaceholder},
+	{method: "DELETE", target: "/neighbors/" + leasePlaceholder},
+	{method: "GET", target: "/neighbors/" + leasePlaceholder},
+}
+
+// openAPI is the decoded OpenAPI document, with helpers to look things up in it.
+type openAPI map[string]interface{}
+
+// TestOpenAPI sends every exercise, in order, to the handlers of a new service,
+// and checks each response against the OpenAPI document: its route, method,
+// status code, content type and body must all be documented, and routes
+// marked as deprecated must say so in their headers. Every documented
+// operation must be exercised.
+func TestOpenAPI(t *testing.T) {
+	var doc openAPI
+	if err := json.Unmarshal([]byte(openAPIDocument), &doc); err != nil {
+		t.Fatalf("decoding the OpenAPI document: %v", err)
+	}
+	var service = NewSimulatedService(startingThroughput, startingSoft, startingHard)
+	defer service.Close()
+	service.Seed(1)
+	// Take a sample, so the history has a point to check.
+	service.recordSample(time.Now())
+	// A single worker is plenty for the requests to /v1/work, which are sent one at a time.
+	var pool = NewWorkPool(1, 0, time.Millisecond)
+
+	var lease string
+	var exercised = make(map[string]bool)
+	for _, e := range exercises {
+		var target = strings.Replace(e.target, leasePlaceholder, lease, -1)
+		t.Run(e.method+" "+target, func(t *testing.T) {
+			var req = httptest.NewRequest(e.method, target, strings.NewReader(e.body))
+			var recorder = httptest.NewRecorder()
+			service.StrictHealth = e.strictHealth
+			if e.real {
+				service.SetWork(pool)
+			} else {
+				service.SetWork(nil)
+			}
+			if e.timeout > 0 {
+				var ctx, cancel = context.WithTimeout(req.Context(), e.timeout)
+				req = req.WithContext(ctx)
+				service.ServeHTTP(recorder, req)
+				cancel()
+			} else {
+				service.ServeHTTP(recorder, req)
+			}
+
+			var route, _ = service.route(req.URL.Path)
+			exercised[route+" "+e.method] = true
+			for _, err := range doc.checkResponse(route, e.method, recorder) {
+				t.Error(err)
+			}
+			if id := leaseID(recorder.Body.Bytes()); id != "" {
+				lease = id
+			}
+		})
+	}
+	for _, operation := range doc.operations() {
+		if !exercised[operation] {
+			t.Errorf("%s: documented, but never exercised", operation)
+		}
+	}
+}
+
+// leaseID returns the ID of the lease in a neighbor add response, if there is one.
+func leaseID(body []byte) string {
+	var response struct {
+		LeaseID  string
+		Neighbor struct {
+			ID string `json:"id"`
+		} `json:"neighbor"`
+	}
+	if json.Unmarshal(body, &response) != nil {
+		return ""
+	}
+	if response.LeaseID != "" {
+		return response.LeaseID
+	}
+	return response.Neighbor.ID
+}
+
+// operations lists every documented operation as "path METHOD", sorted.
+func (doc openAPI) operations() []string {
+	var list []string
+	for path, item := range object(doc["paths"]) {
+		for method := range object(item) {
+			if method != "parameters" {
+				list = append(list, path+" "+strings.ToUpper(method))
+			}
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+// checkResponse returns every way in which the response differs from the document.
+func (doc openAPI) checkResponse(route, method string, recorder *httptest.ResponseRecorder) []error {
+	var item = object(object(doc["paths"])[route])
+	if item == nil {
+		return []error{fmt.Errorf("route %s isn't documented", route)}
+	}
+	var operation = object(item[strings.ToLower(method)])
+	if operation == nil {
+		return doc.checkMethodNotAllowed(route, recorder)
+	}
+	var errs []error
+	var deprecated, _ = operation["deprecated"].(bool)
+	if deprecated != (recorder.Header().Get("Deprecation") == "true") {
+		errs = append(errs, fmt.Errorf("documented as deprecated=%v, but the Deprecation header is %q", deprecated, recorder.Header().Get("Deprecation")))
+	}
+
+	var status = fmt.Sprint(recorder.Code)
+	var response = doc.resolve(object(object(operation["responses"])[status]))
+	if response == nil {
+		return append(errs, fmt.Errorf("status %s isn't documented", status))
+	}
+	var content = object(response["content"])
+	var mediaType, _, _ = mime.ParseMediaType(recorder.Header().Get("Content-Type"))
+	if strings.HasPrefix(route, api.Version+"/") && mediaType != "application/json" && content[mediaType] == nil {
+		errs = append(errs, fmt.Errorf("expected a JSON content type, got %q", mediaType))
+	}
+
+	// Handlers which predate /v1 don't set a content type, so their JSON is
+	// sniffed as text. A body which decodes as JSON is checked as JSON.
+	var body interface{}
+	var isJSON = json.Unmarshal(recorder.Body.Bytes(), &body) == nil
+	var jsonSchema = object(object(content["application/json"])["schema"])
+	switch {
+	case mediaType == "text/event-stream" && content[mediaType] != nil:
+		errs = append(errs, doc.checkEvents(object(content[mediaType]), recorder.Body.String())...)
+	case isJSON && content["application/json"] != nil:
+		errs = append(errs, doc.validate(jsonSchema, body, "body")...)
+	case mediaType == "application/json":
+		errs = append(errs, fmt.Errorf("the %s body isn't valid JSON: %q", status, recorder.Body.String()))
+	case content[mediaType] == nil:
+		errs = append(errs, fmt.Errorf("content type %q isn't documented for status %s", mediaType, status))
+	}
+	return errs
+}
+
+// checkEvents returns every way in which the Server-Sent Events in the body
+// differ from the x-events extension of the media type, which gives the
+// schema of each event's data. Every documented event must be sent.
+func (doc openAPI) checkEvents(media map[string]interface{}, body string) []error {
+	var schemas = object(media["x-events"])
+	var errs []error
+	var seen = make(map[string]bool)
+	for _, block := range strings.Split(body, "\n\n") {
+		var name, data string
+		for _, line := range strings.Split(block, "\n") {
+			if strings.HasPrefix(line, "event: ") {
+				name = strings.TrimPrefix(line, "event: ")
+			} else if strings.HasPrefix(line, "data: ") {
+				data += strings.TrimPrefix(line, "data: ")
+			}
+		}
+		if name == "" {
+			continue
+		}
+		seen[name] = true
+		var schema = object(schemas[name])
+		if schema == nil {
+			errs = append(errs, fmt.Errorf("event %q isn't documented", name))
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(data), &value); err != nil {
+			errs = append(errs, fmt.Errorf("the data of event %q isn't valid JSON: %q", name, data))
+			continue
+		}
+		errs = append(errs, doc.validate(schema, value, name)...)
+	}
+	for name := range schemas {
+		if !seen[name] {
+			errs = append(errs, fmt.Errorf("event %q is documented, but wasn't sent", name))
+		}
+	}
+	return errs
+}
+
+// checkMethodNotAllowed returns every way in which the response to a method
+// which isn't documented for the route differs from a 405.
+func (doc openAPI) checkMethodNotAllowed(route string, recorder *httptest.ResponseRecorder) []error {
+	if recorder.Code != http.StatusMethodNotAllowed {
+		return []error{fmt.Errorf("the method isn't documented, but the status is %d rather than 405", recorder.Code)}
+	}
+	if !strings.HasPrefix(route, api.Version+"/") {
+		return nil
+	}
+	var errs []error
+	if recorder.Header().Get("Allow") == "" {
+		errs = append(errs, fmt.Errorf("expected an Allow header with the 405"))
+	}
+	var body interface{}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		return append(errs, fmt.Errorf("the 405 body isn't valid JSON: %v", err))
+	}
+	var schema = map[string]interface{}{"$ref": "#/components/schemas/ErrorResponse"}
+	return append(errs, doc.validate(schema, body, "body")...)
+}
+
+// resolve follows the $ref of a schema, parameter or response, if it has one.
+func (doc openAPI) resolve(value map[string]interface{}) map[string]interface{} {
+	var ref, ok = value["$ref"].(string)
+	if !ok {
+		return value
+	}
+	var resolved interface{} = map[string]interface{}(doc)
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		resolved = object(resolved)[part]
+	}
+	return doc.resolve(object(resolved))
+}
+
+// validate returns every way in which the value breaks the schema.
+// It understands the parts of JSON Schema used by the document.
+func (doc openAPI) validate(schema map[string]interface{}, value interface{}, at string) []error {
+	schema = doc.resolve(schema)
+	if schema == nil {
+		return []error{fmt.Errorf("%s: no schema", at)}
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && !contains(enum, value) {
+		return []error{fmt.Errorf("%s: %v is not one of %v", at, value, enum)}
+	}
+	switch schema["type"] {
+	case "object":
+		var fields, ok = value.(map[string]interface{})
+		if !ok {
+			return []error{fmt.Errorf("%s: expected an object, got %T", at, value)}
+		}
+		var errs []error
+		var properties = object(schema["properties"])
+		for _, name := range list(schema["required"]) {
+			if _, ok := fields[name.(string)]; !ok {
+				errs = append(errs, fmt.Errorf("%s: missing required field %q", at, name))
+			}
+		}
+		for name, field := range fields {
+			if property, ok := properties[name]; ok {
+				errs = append(errs, doc.validate(object(property), field, at+"."+name)...)
+			} else if additional, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+				errs = append(errs, doc.validate(additional, field, at+"."+name)...)
+			} else if schema["additionalProperties"] == false {
+				errs = append(errs, fmt.Errorf("%s: undocumented field %q", at, name))
+			}
+		}
+		return errs
+	case "array":
+		var items, ok = value.([]interface{})
+		if !ok {
+			return []error{fmt.Errorf("%s: expected an array, got %T", at, value)}
+		}
+		var errs []error
+		for i, item := range items {
+			errs = append(errs, doc.validate(object(schema["items"]), item, fmt.Sprintf("%s[%d]", at, i))...)
+		}
+		return errs
+	case "string":
+		var s, ok = value.(string)
+		if !ok {
+			return []error{fmt.Errorf("%s: expected a string, got %T", at, value)}
+		}
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				return []error{fmt.Errorf("%s: %v", at, err)}
+			}
+		}
+	case "integer", "number":
+		var n, ok = value.(float64)
+		if !ok {
+			return []error{fmt.Errorf("%s: expected a number, got %T", at, value)}
+		}
+		if schema["type"] == "integer" && n != math.Trunc(n) {
+			return []error{fmt.Errorf("%s: expected an integer, got %v", at, n)}
+		}
+		if minimum, ok := schema["minimum"].(float64); ok && n < minimum {
+			return []error{fmt.Errorf("%s: %v is less than the minimum of %v", at, n, minimum)}
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && n > maximum {
+			return []error{fmt.Errorf("%s: %v is more than the maximum of %v", at, n, maximum)}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return []error{fmt.Errorf("%s: expected a boolean, got %T", at, value)}
+		}
+	}
+	return nil
+}
+
+func object(value interface{}) map[string]interface{} {
+	var m, _ = value.(map[string]interface{})
+	return m
+}
+
+func list(value interface{}) []interface{} {
+	var l, _ = value.([]interface{})
+	return l
+}
+
+func contains(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
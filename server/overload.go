@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
+)
+
+const (
+	// overloadCheckInterval is how often the watchdog checks the load.
+	overloadCheckInterval = 100 * time.Millisecond
+	// minWarmUpCapacity is the fraction of capacity available right after starting.
+	minWarmUpCapacity = 0.1
+	// overloadExitCode is the exit code used when the service dies of overload.
+	overloadExitCode = 3
+)
+
+// An OverloadPolicy makes an overloaded service actually fall over,
+// so that Nomad's restart and reschedule policies kick in.
+type OverloadPolicy struct {
+	// CrashAfter is how long the load must stay past the hard limit
+	// before the service dies. Zero disables crashing.
+	CrashAfter time.Duration
+	// DeathSpiral is how long the service lingers, reporting itself dead,
+	// before it exits.
+	DeathSpiral time.Duration
+}
+
+// WatchOverload checks the load until it has stayed past the hard limit for
+// the policy's CrashAfter duration. The service then enters its death spiral,
+// after which exit is called with a non-zero code.
+func (service *SimulatedService) WatchOverload(policy OverloadPolicy, exit func(int)) {
+	var ticker = time.NewTicker(overloadCheckInterval)
+	defer ticker.Stop()
+	var overloadedSince time.Time
+	for now := range ticker.C {
+		var load, _ = service.load.Load(now)
+		if !service.Snapshot().Overloaded(load) {
+			overloadedSince = time.Time{}
+			continue
+		}
+		if overloadedSince.IsZero() {
+			overloadedSince = now
+		}
+		if now.Sub(overloadedSince) >= policy.CrashAfter {
+			break
+		}
+	}
+	log.Printf("Overloaded for %v, entering a %v death spiral", policy.CrashAfter, policy.DeathSpiral)
+	atomic.StoreInt32(&service.dying, 1)
+	service.changes.Notify()
+	service.events.Record(api.Event{
+		Type:    api.EventDying,
+		Message: fmt.Sprintf("Overloaded for %v, exiting after a %v death spiral", policy.CrashAfter, policy.DeathSpiral),
+	})
+	time.Sleep(policy.DeathSpiral)
+	log.Printf("Exiting with code %d after dying of overload", overloadExitCode)
+	exit(overloadExitCode)
+}
+
+// IsDying returns true once the service has been overloaded for long enough
+// to fall over. A dying service is dead, whatever the load.
+func (service *SimulatedService) IsDying() bool {
+	return atomic.LoadInt32(&service.dying) == 1
+}
+
+// warmUpFactor returns, as a fraction from 0 to 1, how much of its capacity
+// the service has at the given time. Capacity ramps up linearly from
+// minWarmUpCapacity over the warm-up period after the service starts.
+func (service *SimulatedService) warmUpFactor(now time.Time) float64 {
+	if service.WarmUp <= 0 {
+		return 1
+	}
+	var elapsed = now.Sub(service.startedAt)
+	if elapsed >= service.WarmUp {
+		return 1
+	}
+	var progress = float64(elapsed) / float64(service.WarmUp)
+	return minWarmUpCapacity + (1-minWarmUpCapacity)*progress
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+// Metrics counts the events which can't be derived from the state of a
+// SimulatedService, so they can be exposed to Prometheus.
+type Metrics struct {
+	sync.Mutex
+	// neighborCalls counts neighbor calls by operation.
+	neighborCalls map[string]uint64
+	// expirations counts the neighbors whose leases were reaped.
+	expirations uint64
+	// requests counts HTTP requests by route and status code.
+	requests map[requestKey]uint64
+}
+
+type requestKey struct {
+	route  string
+	status int
+}
+
+// NewMetrics is the constructor for Metrics.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		neighborCalls: make(map[string]uint64),
+		requests:      make(map[requestKey]uint64),
+	}
+}
+
+// ObserveNeighborCall counts a neighbor add, renew or remove, or a rejected add.
+func (metrics *Metrics) ObserveNeighborCall(operation string) {
+	metrics.Lock()
+	defer metrics.Unlock()
+	metrics.neighborCalls[operation]++
+}
+
+// ObserveExpiration counts a neighbor whose lease was reaped.
+func (metrics *Metrics) ObserveExpiration() {
+	metrics.Lock()
+	defer metrics.Unlock()
+	metrics.expirations++
+}
+
+// ObserveRequest counts an HTTP request to the given route.
+func (metrics *Metrics) ObserveRequest(route string, status int) {
+	metrics.Lock()
+	defer metrics.Unlock()
+	metrics.requests[requestKey{route, status}]++
+}
+
+// statusRecorder remembers the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader fulfills the http.ResponseWriter interface.
+func (recorder *statusRecorder) WriteHeader(status int) {
+	recorder.status = status
+	recorder.ResponseWriter.WriteHeader(status)
+}
+
+// Flush fulfills the http.Flusher interface, so that streams pass through the recorder.
+func (recorder *statusRecorder) Flush() {
+	if flusher, ok := recorder.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (service *SimulatedService) handlePrometheus(w http.ResponseWriter, req *http.Request) {
+	// Liveness depends on the load, which Prometheus can pass as a scrape param.
+	// Otherwise, the load held by the server is used.
+	var load, err = service.getLoad(req)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error parsing load param: %v", err), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	service.WritePrometheus(w, load)
+}
+
+// WritePrometheus writes the state of this service to w
+// in the Prometheus text exposition format.
+func (service *SimulatedService) WritePrometheus(w io.Writer, load uint64) {
+	var state = service.Snapshot()
+	var alive uint64
+	if service.IsAlive(load) {
+		alive = 1
+	}
+	writeGauge(w, "simulated_service_max_throughput", "Requests per second processable without noisy neighbors.", state.MaxThroughput)
+	writeGauge(w, "simulated_service_available_throughput", "Requests per second processable once noisy neighbors are accounted for.", state.AvailableThroughput())
+	writeGauge(w, "simulated_service_soft_limit", "Load at which the service starts to degrade, without noisy neighbors.", state.SoftLimit)
+	writeGauge(w, "simulated_service_hard_limit", "Load past which the service falls over, without noisy neighbors.", state.HardLimit)
+	writeGauge(w, "simulated_service_modified_soft_limit", "Soft limit once noisy neighbors are accounted for.", state.ModifiedSoftLimit())
+	writeGauge(w, "simulated_service_modified_hard_limit", "Hard limit once noisy neighbors are accounted for.", state.ModifiedHardLimit())
+	writeGauge(w, "simulated_service_stolen_cpu_percent", "Percentage of CPU stolen by noisy neighbors.", state.Stolen.CPU)
+	fmt.Fprintln(w, "# HELP simulated_service_stolen_resource_percent Percentage of each resource stolen by noisy neighbors.")
+	fmt.Fprintln(w, "# TYPE simulated_service_stolen_resource_percent gauge")
+	for _, resource := range AllResources {
+		fmt.Fprintf(w, "simulated_service_stolen_resource_percent{resource=%q} %d\n", resource, state.Stolen.Get(resource))
+	}
+	writeGauge(w, "simulated_service_neighbors", "Number of noisy neighbors holding a lease.", uint64(len(service.Neighbors())))
+	writeGauge(w, "simulated_service_load", "Requests per second offered to the service.", load)
+	writeGauge(w, "simulated_service_alive", "Whether the service is alive at the given load.", alive)
+
+	service.metrics.Lock()
+	defer service.metrics.Unlock()
+
+	fmt.Fprintln(w, "# HELP simulated_service_neighbor_calls_total Noisy neighbor calls by operation, including rejected adds.")
+	fmt.Fprintln(w, "# TYPE simulated_service_neighbor_calls_total counter")
+	for _, operation := range []string{"add", "reject", "renew", "remove"} {
+		fmt.Fprintf(w, "simulated_service_neighbor_calls_total{operation=%q} %d\n", operation, service.metrics.neighborCalls[operation])
+	}
+	fmt.Fprintln(w, "# HELP simulated_service_neighbor_expirations_total Noisy neighbors removed because their lease expired.")
+	fmt.Fprintln(w, "# TYPE simulated_service_neighbor_expirations_total counter")
+	fmt.Fprintf(w, "simulated_service_neighbor_expirations_total %d\n", service.metrics.expirations)
+
+	// Sort the request counters so the output is stable between scrapes.
+	var keys = make([]requestKey, 0, len(service.metrics.requests))
+	for key := range service.metrics.requests {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		return keys[i].status < keys[j].status
+	})
+	fmt.Fprintln(w, "# HELP simulated_service_http_requests_total HTTP requests by route and status code.")
+	fmt.Fprintln(w, "# TYPE simulated_service_http_requests_total counter")
+	for _, key := range keys {
+		fmt.Fprintf(w, "simulated_service_http_requests_total{route=%q,code=\"%d\"} %d\n", key.route, key.status, service.metrics.requests[key])
+	}
+}
+
+// writeGauge writes a single unlabelled gauge, with its help and type lines.
+func writeGauge(w io.Writer, name, help string, value uint64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(w, "%s %d\n", name, value)
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// lockedSource is a rand.Source which is safe to share between requests.
+// The sources in math/rand are not, outside of the global one.
+type lockedSource struct {
+	sync.Mutex
+	source rand.Source
+}
+
+// NewRand returns a goroutine-safe random number generator with the given seed.
+// Two generators with the same seed produce the same numbers.
+func NewRand(seed int64) *rand.Rand {
+	return rand.New(&lockedSource{source: rand.NewSource(seed)})
+}
+
+// Int63 fulfills the rand.Source interface.
+func (src *lockedSource) Int63() int64 {
+	src.Lock()
+	defer src.Unlock()
+	return src.source.Int63()
+}
+
+// Seed fulfills the rand.Source interface.
+func (src *lockedSource) Seed(seed int64) {
+	src.Lock()
+	defer src.Unlock()
+	src.source.Seed(seed)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSamplingKeepsSeededThroughputs(t *testing.T) {
+	// Past the soft limit, the step model draws the throughput at random.
+	const load = 1800
+	var quiet, sampled = NewSimulatedService(1000, 1500, 2000), NewSimulatedService(1000, 1500, 2000)
+	defer quiet.Close()
+	defer sampled.Close()
+	quiet.Seed(1)
+	sampled.Seed(1)
+	// Only the sampled service holds the load, so only its samples draw.
+	sampled.SetLoad(load)
+	for i := 0; i < 20; i++ {
+		sampled.recordSample(time.Now())
+		sampled.sampleThroughput(time.Now())
+		sampled.ExplainThroughput(load)
+		if want, got := quiet.CalculateThroughput(load), sampled.CalculateThroughput(load); got != want {
+			t.Fatalf("throughput %d = %d after sampling, want %d as without sampling", i, got, want)
+		}
+	}
+}
+
+func TestExplainReportsTheServedDraw(t *testing.T) {
+	// Past the soft limit, the step model draws the throughput at random.
+	const load = 1800
+	var service = NewSimulatedService(1000, 1500, 2000)
+	defer service.Close()
+	for i := 0; i < 20; i++ {
+		var served = service.CalculateThroughput(load)
+		var explanation = service.ExplainThroughput(load)
+		if explanation.Throughput != served || explanation.Draw == nil || explanation.Draw.Value != served {
+			t.Fatalf("ExplainThroughput(%d) = %d drawn from %+v, want %d as served", load, explanation.Throughput, explanation.Draw, served)
+		}
+	}
+	// Under another load, nothing was served, so the explanation draws afresh.
+	if explanation := service.ExplainThroughput(load + 1); explanation.Draw == nil {
+		t.Errorf("ExplainThroughput(%d) has no draw, want a fresh one", load+1)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ReloadOnHangup reloads the config whenever SIGHUP is received, e.g. when
+// Nomad's template stanza re-renders the config file with change_mode = "signal".
+// The config is rebuilt from the same arguments it was first loaded with.
+// Only the capacity limits, throughput model and sensitivities are applied; neighbors, load
+// and everything else are kept. An invalid config is logged and ignored.
+func ReloadOnHangup(service *SimulatedService, current Config, args []string) {
+	var signals = make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	for range signals {
+		var config, err = LoadConfig(args)
+		if err != nil {
+			log.Printf("Rejected reloaded config, keeping the running config: %v", err)
+			continue
+		}
+		// Validated by LoadConfig, so the model can be built.
+		var model, _ = config.ThroughputModel()
+		if err = service.Reconfigure(config.MaxThroughput, config.SoftLimit, config.HardLimit, model, config.Sensitivity()); err != nil {
+			log.Printf("Rejected reloaded config, keeping the running config: %v", err)
+			continue
+		}
+		warnRestartRequired(current, config)
+		for _, s := range settings {
+			if s.reloadable {
+				s.set(&current, s.get(&config))
+			}
+		}
+		log.Printf("Reloaded config: %v", current)
+	}
+}
+
+// warnRestartRequired logs every setting which changed
+// but only takes effect when the server restarts.
+func warnRestartRequired(current, reloaded Config) {
+	for _, s := range settings {
+		if s.reloadable || s.name == "seed" {
+			continue
+		}
+		if s.get(&current) != s.get(&reloaded) {
+			log.Printf("Setting %s changed to %s, but only takes effect after a restart", s.fileKey(), s.get(&reloaded))
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// A Resource is a dimension of the machine which noisy neighbors can steal.
+type Resource string
+
+const (
+	ResourceCPU             Resource = "cpu"
+	ResourceMemoryBandwidth Resource = "memory_bandwidth"
+	ResourceDiskIO          Resource = "disk_io"
+	ResourceNetwork         Resource = "network"
+)
+
+// AllResources lists every resource, in a stable order.
+var AllResources = []Resource{ResourceCPU, ResourceMemoryBandwidth, ResourceDiskIO, ResourceNetwork}
+
+// Resources holds a percentage, from 0 to 100, of each resource.
+// The names of its JSON keys match the URL parameters used by noisy neighbors.
+type Resources struct {
+	CPU             uint64 `json:"cpu"`
+	MemoryBandwidth uint64 `json:"memory_bandwidth"`
+	DiskIO          uint64 `json:"disk_io"`
+	Network         uint64 `json:"network"`
+}
+
+// field returns a pointer to the percentage of the given resource.
+func (resources *Resources) field(resource Resource) *uint64 {
+	switch resource {
+	case ResourceMemoryBandwidth:
+		return &resources.MemoryBandwidth
+	case ResourceDiskIO:
+		return &resources.DiskIO
+	case ResourceNetwork:
+		return &resources.Network
+	default:
+		return &resources.CPU
+	}
+}
+
+// Get returns the percentage of the given resource.
+func (resources Resources) Get(resource Resource) uint64 {
+	return *resources.field(resource)
+}
+
+// Add returns the sum of both sets of resources, saturating rather than wrapping around.
+func (resources Resources) Add(other Resources) Resources {
+	for _, resource := range AllResources {
+		var sum = resources.Get(resource) + other.Get(resource)
+		if sum < resources.Get(resource) {
+			sum = math.MaxUint64
+		}
+		*resources.field(resource) = sum
+	}
+	return resources
+}
+
+// Sub returns the difference of both sets of resources, stopping at zero rather than wrapping around.
+func (resources Resources) Sub(other Resources) Resources {
+	for _, resource := range AllResources {
+		var difference uint64
+		if resources.Get(resource) > other.Get(resource) {
+			difference = resources.Get(resource) - other.Get(resource)
+		}
+		*resources.field(resource) = difference
+	}
+	return resources
+}
+
+// IsZero returns true if none of any resource is held.
+func (resources Resources) IsZero() bool {
+	return resources == Resources{}
+}
+
+// describe returns the resources as space-separated key=value pairs, for logging.
+// It isn't named String, since Neighbor embeds Resources and would print as one.
+func (resources Resources) describe() string {
+	var pairs = make([]string, 0, len(AllResources))
+	for _, resource := range AllResources {
+		pairs = append(pairs, fmt.Sprintf("%s=%d", resource, resources.Get(resource)))
+	}
+	return strings.Join(pairs, " ")
+}
+
+// getResources returns the resources named in the request's URL parameters.
+// Each resource is optional, but at least one must be provided.
+func getResources(req *http.Request) (Resources, error) {
+	var resources Resources
+	var provided bool
+	for _, resource := range AllResources {
+		var value = req.FormValue(string(resource))
+		if value == "" {
+			continue
+		}
+		var amount, err = strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return resources, fmt.Errorf("parsing %s: %v", resource, err)
+		}
+		*resources.field(resource) = amount
+		provided = true
+	}
+	if !provided {
+		return resources, fmt.Errorf("expected at least one of %v", AllResources)
+	}
+	return resources, nil
+}
+
+// Sensitivity holds how strongly the theft of each resource slows down a service.
+// A sensitivity of 1 means losing x% of a resource costs x% of the capacity;
+// 0 means the service doesn't notice, and 2 means it suffers twice as much.
+// A cache-heavy service, for example, is more sensitive to memory bandwidth than to CPU.
+type Sensitivity struct {
+	CPU             float64
+	MemoryBandwidth float64
+	DiskIO          float64
+	Network         float64
+}
+
+// DefaultSensitivity only slows a service down when CPU is stolen.
+func DefaultSensitivity() Sensitivity {
+	return Sensitivity{CPU: 1}
+}
+
+// Get returns the sensitivity to the given resource.
+func (sensitivity Sensitivity) Get(resource Resource) float64 {
+	switch resource {
+	case ResourceMemoryBandwidth:
+		return sensitivity.MemoryBandwidth
+	case ResourceDiskIO:
+		return sensitivity.DiskIO
+	case ResourceNetwork:
+		return sensitivity.Network
+	default:
+		return sensitivity.CPU
+	}
+}
+
+// Availability returns, as a fraction from 0 to 1, how much of its capacity
+// the service keeps once the given resources are stolen. Each resource's
+// theft compounds the others.
+func (sensitivity Sensitivity) Availability(stolen Resources) float64 {
+	var availability = 1.0
+	for _, resource := range AllResources {
+		var loss = sensitivity.Get(resource) * float64(stolen.Get(resource)) / 100
+		availability *= math.Max(0, 1-loss)
+	}
+	return math.Min(1, availability)
+}
+
+// Validate returns an error if any sensitivity is negative.
+func (sensitivity Sensitivity) Validate() error {
+	for _, resource := range AllResources {
+		if sensitivity.Get(resource) < 0 {
+			return fmt.Errorf("the %s sensitivity must not be negative", resource)
+		}
+	}
+	return nil
+}
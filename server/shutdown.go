@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
+)
+
+// defaultShutdownTimeout is how long in-flight requests get to finish on shutdown.
+const defaultShutdownTimeout = 5 * time.Second
+
+// Serve runs the server until it fails, or until SIGINT or SIGTERM is received.
+// On a signal, the service stops reporting itself ready, and keeps serving for
+// the drain delay so load balancers and health checks see it isn't ready.
+// A second signal cuts the delay short. In-flight requests to the HTTP server,
+// and to the gRPC server if there is one, are then given until the timeout
+// to finish before the final state is logged. Both servers stop at once.
+func Serve(server *http.Server, grpcServer *grpc.Server, service *SimulatedService, drainDelay, timeout time.Duration) error {
+	var signals = make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	var failed = make(chan error, 1)
+	go func() {
+		failed <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-failed:
+		return err
+	case sig := <-signals:
+		log.Printf("Received %v, draining connections for up to %v", sig, timeout)
+	}
+
+	service.Drain()
+	if drainDelay > 0 {
+		log.Printf("Reporting not ready for %v before shutting down", drainDelay)
+		select {
+		case <-time.After(drainDelay):
+		case sig := <-signals:
+			log.Printf("Received %v, shutting down now", sig)
+		}
+	}
+	// Streams never finish on their own, so they'd hold up the shutdown until the timeout.
+	service.EndStreams()
+	var ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var grpcStopped = make(chan struct{})
+	go func() {
+		if grpcServer != nil {
+			StopGRPC(ctx, grpcServer)
+		}
+		close(grpcStopped)
+	}()
+	var err = server.Shutdown(ctx)
+	<-grpcStopped
+	service.Close()
+	service.LogState()
+	return err
+}
+
+// Drain marks the service as not ready, so that no new traffic is sent its way.
+func (service *SimulatedService) Drain() {
+	atomic.StoreInt32(&service.draining, 1)
+	service.changes.Notify()
+	service.events.Record(api.Event{Type: api.EventDraining, Message: "Draining before shutting down"})
+}
+
+// EndStreams ends every stream of state changes and throughput samples,
+// over Server-Sent Events and gRPC. Subscribers are expected to resubscribe.
+func (service *SimulatedService) EndStreams() {
+	service.endStreams.Do(func() {
+		close(service.streamsEnded)
+	})
+}
+
+// Close stops the service's background goroutines, and ends every stream.
+// The service still answers requests, but expired neighbors are no longer
+// reaped, and neither liveness nor history are recorded.
+func (service *SimulatedService) Close() {
+	service.EndStreams()
+	service.closeOnce.Do(func() {
+		close(service.closed)
+	})
+}
+
+// IsDraining returns true once the service has started shutting down.
+func (service *SimulatedService) IsDraining() bool {
+	return atomic.LoadInt32(&service.draining) == 1
+}
+
+// LogState logs a summary of the service's state, including every neighbor.
+func (service *SimulatedService) LogState() {
+	var state = service.Snapshot()
+	var load, _ = service.load.Load(time.Now())
+	var neighbors = service.Neighbors()
+	log.Printf("Final state: max_throughput=%d soft_limit=%d hard_limit=%d stolen=[%v] load=%d neighbors=%d",
+		state.MaxThroughput, state.SoftLimit, state.HardLimit, state.Stolen.describe(), load, len(neighbors))
+	for _, neighbor := range neighbors {
+		log.Printf("Neighbor %s: %v address=%s label=%q job_id=%q started_at=%v",
+			neighbor.ID, neighbor.Resources.describe(), neighbor.Address, neighbor.Label, neighbor.JobID, neighbor.StartedAt.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,192 @@
+package main
+
+import (
+	"math"
+	"time"
+)
+
+// State is a consistent snapshot of everything which decides how a
+// SimulatedService performs. Every value derived from a State is computed
+// from the same limits and stolen CPU, even while neighbors come and go
+// and the limits are being changed.
+type State struct {
+	MaxThroughput,
+	SoftLimit,
+	HardLimit uint64
+	// Stolen is the total of each resource, as a percentage, held by noisy neighbors.
+	// It can exceed 100, in which case none of that resource is available.
+	Stolen Resources
+	// Sensitivity decides how much the theft of each resource slows the service.
+	Sensitivity Sensitivity
+	Model       ThroughputModel
+	// WarmUp is the fraction of its capacity the service has warmed up to.
+	WarmUp float64
+	// Work, if set, runs the requests to /v1/work, and puts the service in real
+	// mode, where the throughput is measured rather than modeled.
+	Work *WorkPool
+	// Contention is the CPU contention last measured on the host, and
+	// ContentionWeight how much it counts towards AvailableCPU, from 0 to 1.
+	// With a weight of 0, only the simulated neighbors count.
+	Contention       CPUContention
+	ContentionWeight float64
+	// LastDraw is the last throughput served which was drawn at random,
+	// so that explaining it reports the same draw.
+	LastDraw *ServedDraw
+}
+
+// A ServedDraw is a throughput drawn at random and served, along with what
+// it was drawn under.
+type ServedDraw struct {
+	Load        uint64
+	Capacity    Capacity
+	Model       ThroughputModel
+	Explanation Explanation
+}
+
+// Snapshot returns the current state of the service.
+func (service *SimulatedService) Snapshot() State {
+	service.stateLock.RLock()
+	var state = service.state
+	service.stateLock.RUnlock()
+	state.WarmUp = service.warmUpFactor(time.Now())
+	return state
+}
+
+// updateState applies the update to the service's state while holding the lock,
+// so that no reader sees a partially applied update.
+// If the update returns an error, the state is left untouched.
+// Otherwise, watchers are told the state changed.
+func (service *SimulatedService) updateState(update func(state *State) error) (before, after State, err error) {
+	service.stateLock.Lock()
+	before = service.state
+	after = before
+	if err = update(&after); err != nil {
+		service.stateLock.Unlock()
+		return before, before, err
+	}
+	service.state = after
+	service.stateLock.Unlock()
+	service.changes.Notify()
+	return before, after, nil
+}
+
+// recordDraw keeps the throughput last served at random in the state.
+// Watchers aren't told, since no view of the state includes it.
+func (service *SimulatedService) recordDraw(draw ServedDraw) {
+	service.stateLock.Lock()
+	service.state.LastDraw = &draw
+	service.stateLock.Unlock()
+}
+
+// steal adds the resources to the stolen resources, and returns the stolen
+// resources before and after. If any stolen resource would exceed the
+// admission ceiling of the QoS class, nothing is stolen and an
+// AdmissionError is returned.
+func (service *SimulatedService) steal(resources Resources, qos QoS) (before, after Resources, err error) {
+	var ceiling = service.Admission.Ceiling(qos)
+	var old, new, rejected = service.updateState(func(state *State) error {
+		var stolen = state.Stolen.Add(resources)
+		for _, resource := range AllResources {
+			if resources.Get(resource) > 0 && stolen.Get(resource) > ceiling {
+				return &AdmissionError{
+					QoS:       qos,
+					Resource:  resource,
+					Requested: resources.Get(resource),
+					Stolen:    state.Stolen.Get(resource),
+					Ceiling:   ceiling,
+				}
+			}
+		}
+		state.Stolen = stolen
+		return nil
+	})
+	return old.Stolen, new.Stolen, rejected
+}
+
+// restore removes the resources from the stolen resources, and returns the stolen resources after.
+// Restoring more than was stolen leaves nothing stolen, rather than wrapping around.
+func (service *SimulatedService) restore(resources Resources) Resources {
+	var _, new, _ = service.updateState(func(state *State) error {
+		state.Stolen = state.Stolen.Sub(resources)
+		return nil
+	})
+	return new.Stolen
+}
+
+// AvailableCPU returns, as a fraction from 0 to 1, the amount of CPU
+// available to this service. Noisy neighbors reduce the amount of CPU available,
+// as does the contention measured on the host, in proportion to its weight.
+func (state State) AvailableCPU() float64 {
+	var weight = state.ContentionWeight
+	return (1-weight)*state.SimulatedCPU() + weight*state.Contention.Available
+}
+
+// SimulatedCPU returns, as a fraction from 0 to 1, the amount of CPU
+// left to this service by the simulated noisy neighbors.
+func (state State) SimulatedCPU() float64 {
+	if state.Stolen.CPU >= 100 {
+		return 0
+	}
+	return float64(100-state.Stolen.CPU) / 100.0
+}
+
+// Availability returns, as a fraction from 0 to 1, how much of its capacity
+// the service keeps once its sensitivity to each stolen resource is accounted for.
+func (state State) Availability() float64 {
+	var stolen = state.Stolen
+	// The CPU lost to measured contention counts as stolen.
+	if state.ContentionWeight > 0 {
+		stolen.CPU = uint64(math.Round(100 * (1 - state.AvailableCPU())))
+	}
+	return state.Sensitivity.Availability(stolen)
+}
+
+// AvailableThroughput returns the number of requests per second processable
+// by this service. It's value is the max throughput modified by the stolen resources.
+func (state State) AvailableThroughput() uint64 {
+	return state.scaleDown(state.MaxThroughput)
+}
+
+// ModifiedSoftLimit returns the soft limit once noisy neighbors have been accounted for.
+func (state State) ModifiedSoftLimit() uint64 {
+	return state.scaleDown(state.SoftLimit)
+}
+
+// ModifiedHardLimit returns the hard limit once noisy neighbors have been accounted for.
+func (state State) ModifiedHardLimit() uint64 {
+	return state.scaleDown(state.HardLimit)
+}
+
+// Overloaded returns true if the load is past the modified hard limit, where the
+// service falls over. It's the one threshold for liveness, throughput and crashes.
+func (state State) Overloaded(load uint64) bool {
+	return load > state.ModifiedHardLimit()
+}
+
+// Capacity returns the limits passed to the throughput model.
+func (state State) Capacity() Capacity {
+	return Capacity{
+		Throughput: state.AvailableThroughput(),
+		SoftLimit:  state.ModifiedSoftLimit(),
+		HardLimit:  state.ModifiedHardLimit(),
+	}
+}
+
+// warmUp returns the fraction of its capacity the service has warmed up to,
+// treating a value outside (0, 1] as fully warmed up.
+func (state State) warmUp() float64 {
+	if state.WarmUp <= 0 || state.WarmUp > 1 {
+		return 1
+	}
+	return state.WarmUp
+}
+
+// scaleDown takes the provided metric (throughput, soft limit, hard limit)
+// and adjusts it to reflect the new limit provided by the noisy neighbor.
+func (state State) scaleDown(metric uint64) uint64 {
+	// Scale down the metric in proportion to the resources left,
+	// and to how warmed up the service is.
+	var scaledMetric = float64(metric) * state.Availability() * state.warmUp()
+	// Round, and then cast.
+	return uint64(math.Round(scaledMetric))
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"math"
+	"sync"
+	"testing"
+)
+
+func TestResourcesAddSaturates(t *testing.T) {
+	var tests = []struct {
+		name        string
+		left, right Resources
+		want        Resources
+	}{
+		{"zero", Resources{}, Resources{}, Resources{}},
+		{"sum", Resources{CPU: 30, DiskIO: 5}, Resources{CPU: 20, Network: 7}, Resources{CPU: 50, DiskIO: 5, Network: 7}},
+		{"past 100", Resources{CPU: 80}, Resources{CPU: 40}, Resources{CPU: 120}},
+		{"overflow", Resources{CPU: math.MaxUint64 - 1, MemoryBandwidth: 1}, Resources{CPU: 2, MemoryBandwidth: 1}, Resources{CPU: math.MaxUint64, MemoryBandwidth: 2}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.left.Add(test.right); got != test.want {
+				t.Errorf("%+v.Add(%+v) = %+v, want %+v", test.left, test.right, got, test.want)
+			}
+		})
+	}
+}
+
+func TestResourcesSubStopsAtZero(t *testing.T) {
+	var tests = []struct {
+		name        string
+		left, right Resources
+		want        Resources
+	}{
+		{"zero", Resources{}, Resources{}, Resources{}},
+		{"difference", Resources{CPU: 50, Network: 9}, Resources{CPU: 20, Network: 9}, Resources{CPU: 30}},
+		{"more than held", Resources{CPU: 10, DiskIO: 3}, Resources{CPU: 40, DiskIO: 1}, Resources{DiskIO: 2}},
+		{"nothing held", Resources{}, Resources{MemoryBandwidth: 5}, Resources{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.left.Sub(test.right); got != test.want {
+				t.Errorf("%+v.Sub(%+v) = %+v, want %+v", test.left, test.right, got, test.want)
+			}
+		})
+	}
+}
+
+func TestSimulatedCPU(t *testing.T) {
+	var tests = []struct {
+		stolen uint64
+		want   float64
+	}{
+		{0, 1},
+		{25, 0.75},
+		{100, 0},
+		{150, 0},
+		{math.MaxUint64, 0},
+	}
+	for _, test := range tests {
+		var state = State{Stolen: Resources{CPU: test.stolen}}
+		if got := state.SimulatedCPU(); got != test.want {
+			t.Errorf("SimulatedCPU() with %d stolen = %v, want %v", test.stolen, got, test.want)
+		}
+		if got := state.AvailableCPU(); got != test.want {
+			t.Errorf("AvailableCPU() with %d stolen = %v, want %v", test.stolen, got, test.want)
+		}
+	}
+}
+
+func TestConcurrentNeighbors(t *testing.T) {
+	var service = NewSimulatedService(1000, 1500, 2000)
+	defer service.Close()
+	const workers, rounds = 8, 50
+
+	var wg sync.WaitGroup
+	var done = make(chan struct{})
+	// Readers check that no snapshot sees more stolen than the neighbors could hold.
+	var readers sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				if stolen := service.Snapshot().Stolen; stolen.CPU > workers || stolen.Network > workers {
+					t.Errorf("Snapshot().Stolen = %+v, want at most %d of each resource", stolen, workers)
+					return
+				}
+				service.Neighbors()
+			}
+		}()
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for round := 0; round < rounds; round++ {
+				var neighbor, _, err = service.AddNeighbor(Neighbor{Resources: Resources{CPU: 1, Network: 1}, QoS: QoSGuaranteed}, defaultLeaseTTL)
+				if err != nil {
+					t.Errorf("AddNeighbor() = %v", err)
+					return
+				}
+				if _, ok := service.RemoveNeighbor(neighbor.ID); !ok {
+					t.Errorf("RemoveNeighbor(%q) found no neighbor", neighbor.ID)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+	readers.Wait()
+
+	if stolen := service.Stolen(); !stolen.IsZero() {
+		t.Errorf("Stolen() = %+v once every neighbor was removed, want nothing", stolen)
+	}
+	if neighbors := service.Neighbors(); len(neighbors) != 0 {
+		t.Errorf("Neighbors() = %v once every neighbor was removed, want none", neighbors)
+	}
+}
+
+func TestUpdateLimits(t *testing.T) {
+	var limit = func(value uint64) *uint64 { return &value }
+	var tests = []struct {
+		name    string
+		update  LimitsUpdate
+		want    [3]uint64
+		wantErr bool
+	}{
+		{"nothing", LimitsUpdate{}, [3]uint64{1000, 1500, 2000}, false},
+		{"throughput", LimitsUpdate{MaxThroughput: limit(500)}, [3]uint64{500, 1500, 2000}, false},
+		{"both limits", LimitsUpdate{SoftLimit: limit(2500), HardLimit: limit(3000)}, [3]uint64{1000, 2500, 3000}, false},
+		{"zero throughput", LimitsUpdate{MaxThroughput: limit(0)}, [3]uint64{1000, 1500, 2000}, true},
+		{"soft past hard", LimitsUpdate{SoftLimit: limit(2500)}, [3]uint64{1000, 1500, 2000}, true},
+		{"hard under soft", LimitsUpdate{HardLimit: limit(1000)}, [3]uint64{1000, 1500, 2000}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var service = NewSimulatedService(1000, 1500, 2000)
+			defer service.Close()
+			var _, err = service.UpdateLimits(test.update)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("UpdateLimits() error = %v, want an error: %v", err, test.wantErr)
+			}
+			var throughput, soft, hard = service.Limits()
+			if got := [3]uint64{throughput, soft, hard}; got != test.want {
+				t.Errorf("Limits() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestConcurrentUpdateLimitsKeepsEveryField(t *testing.T) {
+	var service = NewSimulatedService(1000, 1500, 2000)
+	defer service.Close()
+	var throughput, hard = uint64(500), uint64(3000)
+
+	// Each update changes one limit. Were the merge done outside the lock,
+	// an update could write back the other limit's stale value.
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if _, err := service.UpdateLimits(LimitsUpdate{MaxThroughput: &throughput}); err != nil {
+				t.Errorf("UpdateLimits() = %v", err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, err := service.UpdateLimits(LimitsUpdate{HardLimit: &hard}); err != nil {
+				t.Errorf("UpdateLimits() = %v", err)
+			}
+			service.Snapshot()
+		}()
+	}
+	wg.Wait()
+
+	if gotThroughput, gotSoft, gotHard := service.Limits(); gotThroughput != 500 || gotSoft != 1500 || gotHard != 3000 {
+		t.Errorf("Limits() = %d, %d, %d, want 500, 1500, 3000", gotThroughput, gotSoft, gotHard)
+	}
+}
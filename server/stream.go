@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
+)
+
+const (
+	// defaultStreamInterval is how often a stream sends a throughput sample
+	// when the subscriber doesn't ask for an interval.
+	defaultStreamInterval = time.Second
+	// minStreamInterval is the shortest interval a subscriber may ask for.
+	minStreamInterval = 100 * time.Millisecond
+	// streamRetry is how long an EventSource waits before resubscribing
+	// once a stream ends.
+	streamRetry = 500 * time.Millisecond
+)
+
+// handleV1Stream sends Server-Sent Events: a state event straight away and
+// whenever the state changes, and a throughput event every interval.
+// The write timeout would cut the stream off mid-event, so the stream ends
+// cleanly a little before it, and the subscriber is expected to resubscribe.
+func (service *SimulatedService) handleV1Stream(w http.ResponseWriter, req *http.Request) {
+	var flusher, ok = w.(http.Flusher)
+	if !ok {
+		writeV1Error(w, &api.Error{Status: http.StatusInternalServerError, Code: api.CodeInternal, Message: "Streaming isn't supported."})
+		return
+	}
+	var interval = defaultStreamInterval
+	if value := req.FormValue("interval"); value != "" {
+		var err error
+		if interval, err = time.ParseDuration(value); err != nil {
+			writeV1Error(w, invalidArgument("Error parsing interval param: %v", err))
+			return
+		}
+		if interval < minStreamInterval {
+			writeV1Error(w, invalidArgument("The interval must be at least %v.", minStreamInterval))
+			return
+		}
+	}
+
+	var ctx, cancel = context.WithTimeout(req.Context(), service.Timeout*9/10)
+	defer cancel()
+	var views = make(chan StateView)
+	go service.Watch(ctx.Done(), func(view StateView) error {
+		select {
+		case views <- view:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+	var ticker = time.NewTicker(interval)
+	defer ticker.Stop()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry/time.Millisecond)
+	flusher.Flush()
+	for {
+		var err error
+		select {
+		case <-ctx.Done():
+			return
+		case <-service.streamsEnded:
+			return
+		case view := <-views:
+			err = writeEvent(w, api.StreamState, view.toV1(time.Now()))
+		case now := <-ticker.C:
+			err = writeEvent(w, api.StreamThroughput, service.sampleThroughput(now))
+		}
+		if err != nil {
+			return
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent writes the data, encoded as JSON, as a Server-Sent Event with the given name.
+func writeEvent(w http.ResponseWriter, name string, data interface{}) error {
+	var encoded, err = json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, encoded)
+	return err
+}
+
+// sampleThroughput returns the throughput of the service under the load it holds.
+// Its draws come from the sample generator, so sampling doesn't change the
+// throughputs served to clients of a seeded service.
+func (service *SimulatedService) sampleThroughput(now time.Time) api.ThroughputSample {
+	var load, _ = service.load.Load(now)
+	return api.ThroughputSample{
+		Time:         now,
+		Load:         load,
+		Throughput:   service.explainThroughput(load, service.sampleModel).Throughput,
+		AvailableCPU: 100 * service.AvailableCPU(),
+	}
+}
+
+// toV1 converts the view to its /v1 representation, as seen at the given time.
+func (view StateView) toV1(now time.Time) api.State {
+	return api.State{
+		Time: now,
+		Limits: api.Limits{
+			MaxThroughput:       view.MaxThroughput,
+			SoftLimit:           view.SoftLimit,
+			HardLimit:           view.HardLimit,
+			AvailableThroughput: view.AvailableThroughput,
+			ModifiedSoftLimit:   view.ModifiedSoftLimit,
+			ModifiedHardLimit:   view.ModifiedHardLimit,
+		},
+		Stolen:     api.Resources(view.Stolen),
+		Neighbors:  view.Neighbors,
+		Load:       view.Load,
+		LoadSource: view.LoadSource,
+		Alive:      view.Alive,
+		Ready:      view.Ready,
+		Dying:      view.Dying,
+		Draining:   view.Draining,
+	}
+}
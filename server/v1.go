@@ -0,0 +1,371 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
+)
+
+// legacySuccessors maps each route which predates /v1 to the route replacing it.
+// The legacy routes keep working, but their responses are marked as deprecated.
+var legacySuccessors = map[string]string{
+	"/healthz":            api.Version + "/health",
+	"/readyz":             api.Version + "/ready",
+	"/load":               api.Version + "/load",
+	"/metrics/throughput": api.Version + "/throughput",
+	"/metrics/latency":    api.Version + "/latency",
+	"/neighbors/add":      api.Version + "/neighbors",
+	"/neighbors/renew":    api.Version + "/neighbors/{id}/renew",
+	"/neighbors/remove":   api.Version + "/neighbors/{id}",
+	"/neighbors":          api.Version + "/neighbors",
+	"/neighbors/{id}":     api.Version + "/neighbors/{id}",
+}
+
+// markDeprecated tells the caller of a legacy route which route replaces it.
+func markDeprecated(w http.ResponseWriter, successor string) {
+	w.Header().Set("Deprecation", "true")
+	w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+}
+
+// routeV1 returns the name and handler of a route under /v1.
+func (service *SimulatedService) routeV1(path string) (string, http.HandlerFunc) {
+	var rest = strings.TrimPrefix(path, api.Version)
+	switch {
+	case rest == "/health":
+		return path, allow(service.handleV1Health, http.MethodGet)
+	case rest == "/ready":
+		return path, allow(service.handleV1Ready, http.MethodGet)
+	case rest == "/load":
+		return path, allow(service.handleV1Load, http.MethodGet, http.MethodPost, http.MethodDelete)
+	case rest == "/throughput":
+		return path, allow(service.handleV1Throughput, http.MethodGet)
+	case rest == "/throughput/explain":
+		return path, allow(service.handleV1Explain, http.MethodGet)
+	case rest == "/work":
+		return path, allow(service.handleV1Work, http.MethodGet, http.MethodPost)
+	case rest == "/latency":
+		return path, allow(service.handleV1Latency, http.MethodGet)
+	case rest == "/limits":
+		return path, allow(service.handleV1Limits, http.MethodGet, http.MethodPost)
+	case rest == "/neighbors":
+		return path, allow(service.handleV1Neighbors, http.MethodGet, http.MethodPost)
+	case rest == "/events":
+		return path, allow(service.handleV1Events, http.MethodGet)
+	case rest == "/history":
+		return path, allow(service.handleV1History, http.MethodGet)
+	case rest == "/stream":
+		return path, allow(service.handleV1Stream, http.MethodGet)
+	case strings.HasPrefix(rest, "/neighbors/") && strings.HasSuffix(rest, "/renew"):
+		return api.Version + "/neighbors/{id}/renew", allow(service.handleV1NeighborRenew, http.MethodPost)
+	case strings.HasPrefix(rest, "/neighbors/"):
+		return api.Version + "/neighbors/{id}", allow(service.handleV1Neighbor, http.MethodGet, http.MethodDelete)
+	default:
+		return "unknown", func(w http.ResponseWriter, req *http.Request) {
+			writeV1Error(w, &api.Error{Status: http.StatusNotFound, Code: api.CodeNotFound, Message: "No such route."})
+		}
+	}
+}
+
+// allow responds with 405 to any request whose method isn't one of the given methods.
+func allow(handler http.HandlerFunc, methods ...string) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		for _, method := range methods {
+			if req.Method == method {
+				handler(w, req)
+				return
+			}
+		}
+		w.Header().Set("Allow", strings.Join(methods, ", "))
+		writeV1Error(w, &api.Error{Status: http.StatusMethodNotAllowed, Code: api.CodeMethodNotAllowed, Message: "Method not allowed."})
+	}
+}
+
+// writeV1 writes the response body as JSON with the given status code.
+func writeV1(w http.ResponseWriter, status int, responseBody interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	var encoder = json.NewEncoder(w)
+	// The status has been sent, so there's no way to report a failure to the caller.
+	_ = encoder.Encode(responseBody)
+}
+
+// writeV1Error writes the error in the /v1 error envelope.
+func writeV1Error(w http.ResponseWriter, err *api.Error) {
+	writeV1(w, err.Status, api.ErrorResponse{Error: err})
+}
+
+// invalidArgument returns a 400 error describing the bad input.
+func invalidArgument(format string, args ...interface{}) *api.Error {
+	return &api.Error{Status: http.StatusBadRequest, Code: api.CodeInvalidArgument, Message: fmt.Sprintf(format, args...)}
+}
+
+// neighborNotFound returns a 404 error for the neighbor with the given ID.
+func neighborNotFound(id string) *api.Error {
+	return &api.Error{Status: http.StatusNotFound, Code: api.CodeNotFound, Message: fmt.Sprintf("No neighbor with ID %q.", id)}
+}
+
+// decodeV1 decodes the request's JSON body into v, rejecting unknown fields
+// so that a misspelt field isn't silently ignored. An empty body leaves v as it is.
+func decodeV1(req *http.Request, v interface{}) *api.Error {
+	if req.Body == nil {
+		return nil
+	}
+	var decoder = json.NewDecoder(req.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil && err != io.EOF {
+		return invalidArgument("Error parsing request body: %v", err)
+	}
+	return nil
+}
+
+// getV1Load returns the load from the request's URL parameters,
+// or the load held by the server if there isn't one.
+func (service *SimulatedService) getV1Load(req *http.Request) (uint64, *api.Error) {
+	var load, err = service.getLoad(req)
+	if err != nil {
+		return 0, invalidArgument("Error parsing load param: %v", err)
+	}
+	return load, nil
+}
+
+func (service *SimulatedService) handleV1Health(w http.ResponseWriter, req *http.Request) {
+	var load, err = service.getV1Load(req)
+	if err != nil {
+		writeV1Error(w, err)
+		return
+	}
+	var state = service.Snapshot()
+	var alive = service.IsAlive(load)
+	var responseBody = api.Health{
+		Alive:        alive,
+		Load:         load,
+		AvailableCPU: 100 * state.AvailableCPU(),
+		Stolen:       api.Resources(state.Stolen),
+	}
+	var status = http.StatusOK
+	if !alive {
+		status = http.StatusServiceUnavailable
+	}
+	writeV1(w, status, responseBody)
+}
+
+func (service *SimulatedService) handleV1Ready(w http.ResponseWriter, req *http.Request) {
+	var load, err = service.getV1Load(req)
+	if err != nil {
+		writeV1Error(w, err)
+		return
+	}
+	var ready = service.IsReady(load)
+	var responseBody = api.Readiness{
+		Ready:     ready,
+		Load:      load,
+		SoftLimit: service.ModifiedSoftLimit(),
+	}
+	var status = http.StatusOK
+	if !ready {
+		status = http.StatusServiceUnavailable
+	}
+	writeV1(w, status, responseBody)
+}
+
+func (service *SimulatedService) handleV1Load(w http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodPost:
+		var request api.SetLoadRequest
+		if err := decodeV1(req, &request); err != nil {
+			writeV1Error(w, err)
+			return
+		}
+		// Without a load, an empty body would otherwise pin the load at zero.
+		if request.Load == nil {
+			writeV1Error(w, invalidArgument("Expected a load."))
+			return
+		}
+		service.SetLoad(*request.Load)
+	case http.MethodDelete:
+		service.ClearLoad()
+	}
+	var load, explicit = service.load.Load(time.Now())
+	var source = "measured"
+	if explicit {
+		source = "set"
+	}
+	writeV1(w, http.StatusOK, api.Load{Load: load, Source: source})
+}
+
+func (service *SimulatedService) handleV1Throughput(w http.ResponseWriter, req *http.Request) {
+	var load, err = service.getV1Load(req)
+	if err != nil {
+		writeV1Error(w, err)
+		return
+	}
+	writeV1(w, http.StatusOK, api.Throughput{Load: load, Throughput: service.CalculateThroughput(load)})
+}
+
+func (service *SimulatedService) handleV1Latency(w http.ResponseWriter, req *http.Request) {
+	var load, err = service.getV1Load(req)
+	if err != nil {
+		writeV1Error(w, err)
+		return
+	}
+	writeV1(w, http.StatusOK, api.Latency{
+		Load:  load,
+		Alive: service.IsAlive(load),
+		P50:   toMillis(service.LatencyPercentile(load, 0.5)),
+		P90:   toMillis(service.LatencyPercentile(load, 0.9)),
+		P99:   toMillis(service.LatencyPercentile(load, 0.99)),
+		P999:  toMillis(service.LatencyPercentile(load, 0.999)),
+	})
+}
+
+func (service *SimulatedService) handleV1Limits(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodPost {
+		var update api.LimitsUpdate
+		if err := decodeV1(req, &update); err != nil {
+			writeV1Error(w, err)
+			return
+		}
+		if _, err := service.UpdateLimits(LimitsUpdate(update)); err != nil {
+			writeV1Error(w, invalidArgument("%v", err))
+			return
+		}
+	}
+	var state = service.Snapshot()
+	writeV1(w, http.StatusOK, api.Limits{
+		MaxThroughput:       state.MaxThroughput,
+		SoftLimit:           state.SoftLimit,
+		HardLimit:           state.HardLimit,
+		AvailableThroughput: state.AvailableThroughput(),
+		ModifiedSoftLimit:   state.ModifiedSoftLimit(),
+		ModifiedHardLimit:   state.ModifiedHardLimit(),
+	})
+}
+
+func (service *SimulatedService) handleV1Neighbors(w http.ResponseWriter, req *http.Request) {
+	if req.Method == http.MethodPost {
+		service.handleV1NeighborAdd(w, req)
+		return
+	}
+	var neighbors = service.Neighbors()
+	var responseBody = api.NeighborList{
+		Neighbors: make([]api.Neighbor, 0, len(neighbors)),
+		Stolen:    api.Resources(service.Stolen()),
+	}
+	for _, neighbor := range neighbors {
+		responseBody.Neighbors = append(responseBody.Neighbors, neighbor.toV1())
+	}
+	writeV1(w, http.StatusOK, responseBody)
+}
+
+func (service *SimulatedService) handleV1NeighborAdd(w http.ResponseWriter, req *http.Request) {
+	var request api.NeighborRequest
+	if err := decodeV1(req, &request); err != nil {
+		writeV1Error(w, err)
+		return
+	}
+	var resources = Resources(request.Resources)
+	if resources.IsZero() {
+		writeV1Error(w, invalidArgument("Expected at least one of %v.", AllResources))
+		return
+	}
+	var qos, err = parseQoS(request.QoS)
+	if err != nil {
+		writeV1Error(w, invalidArgument("%v", err))
+		return
+	}
+	ttl, err := leaseTTL(request.TTLSeconds)
+	if err != nil {
+		writeV1Error(w, invalidArgument("%v", err))
+		return
+	}
+	neighbor, previous, err := service.AddNeighbor(Neighbor{
+		Address:   req.RemoteAddr,
+		Resources: resources,
+		Label:     request.Label,
+		JobID:     request.JobID,
+		QoS:       qos,
+	}, ttl)
+	if rejected, ok := err.(*AdmissionError); ok {
+		service.metrics.ObserveNeighborCall("reject")
+		writeV1Error(w, &api.Error{
+			Status:    http.StatusConflict,
+			Code:      api.CodeAdmissionRejected,
+			Message:   rejected.Error(),
+			Admission: rejected.toV1(),
+		})
+		return
+	} else if err != nil {
+		writeV1Error(w, &api.Error{Status: http.StatusInternalServerError, Code: api.CodeInternal, Message: err.Error()})
+		return
+	}
+	service.metrics.ObserveNeighborCall("add")
+	w.Header().Set("Location", api.Version+"/neighbors/"+neighbor.ID)
+	writeV1(w, http.StatusCreated, api.NeighborAdded{
+		Neighbor:       neighbor.toV1(),
+		PreviousStolen: api.Resources(previous),
+		Stolen:         api.Resources(service.Stolen()),
+	})
+}
+
+func (service *SimulatedService) handleV1Neighbor(w http.ResponseWriter, req *http.Request) {
+	var id = strings.TrimPrefix(req.URL.Path, api.Version+"/neighbors/")
+	if req.Method == http.MethodDelete {
+		var neighbor, ok = service.RemoveNeighbor(id)
+		if !ok {
+			writeV1Error(w, neighborNotFound(id))
+			return
+		}
+		service.metrics.ObserveNeighborCall("remove")
+		writeV1(w, http.StatusOK, api.NeighborRemoved{
+			Neighbor: neighbor.toV1(),
+			Stolen:   api.Resources(service.Stolen()),
+		})
+		return
+	}
+	var neighbor, ok = service.Neighbor(id)
+	if !ok {
+		writeV1Error(w, neighborNotFound(id))
+		return
+	}
+	writeV1(w, http.StatusOK, neighbor.toV1())
+}
+
+func (service *SimulatedService) handleV1NeighborRenew(w http.ResponseWriter, req *http.Request) {
+	var id = strings.TrimSuffix(strings.TrimPrefix(req.URL.Path, api.Version+"/neighbors/"), "/renew")
+	var request api.RenewRequest
+	if err := decodeV1(req, &request); err != nil {
+		writeV1Error(w, err)
+		return
+	}
+	ttl, err := leaseTTL(request.TTLSeconds)
+	if err != nil {
+		writeV1Error(w, invalidArgument("%v", err))
+		return
+	}
+	var neighbor, ok = service.RenewNeighbor(id, ttl)
+	if !ok {
+		writeV1Error(w, neighborNotFound(id))
+		return
+	}
+	service.metrics.ObserveNeighborCall("renew")
+	writeV1(w, http.StatusOK, neighbor.toV1())
+}
+
+// toV1 converts the neighbor to its /v1 representation.
+func (neighbor Neighbor) toV1() api.Neighbor {
+	return api.Neighbor{
+		ID:        neighbor.ID,
+		Address:   neighbor.Address,
+		Resources: api.Resources(neighbor.Resources),
+		StartedAt: neighbor.StartedAt,
+		ExpiresAt: neighbor.ExpiresAt,
+		Label:     neighbor.Label,
+		JobID:     neighbor.JobID,
+		QoS:       string(neighbor.QoS),
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
+)
+
+// watchInterval is how often watchers look for changes nobody announces,
+// such as the measured load moving or the service warming up.
+const watchInterval = 250 * time.Millisecond
+
+// changeNotifier wakes every watcher when the service changes.
+// Its zero value is ready to use.
+type changeNotifier struct {
+	sync.Mutex
+	// changed is closed, and replaced, on every change.
+	changed chan struct{}
+}
+
+// Changed returns a channel which is closed on the next change.
+func (notifier *changeNotifier) Changed() <-chan struct{} {
+	notifier.Lock()
+	defer notifier.Unlock()
+	if notifier.changed == nil {
+		notifier.changed = make(chan struct{})
+	}
+	return notifier.changed
+}
+
+// Notify wakes every watcher waiting on Changed.
+func (notifier *changeNotifier) Notify() {
+	notifier.Lock()
+	defer notifier.Unlock()
+	if notifier.changed != nil {
+		close(notifier.changed)
+		notifier.changed = nil
+	}
+}
+
+// A StateView is what a watcher sees of the service at a point in time.
+// It's comparable, so a watcher can tell whether anything has changed.
+type StateView struct {
+	MaxThroughput       uint64
+	SoftLimit           uint64
+	HardLimit           uint64
+	AvailableThroughput uint64
+	ModifiedSoftLimit   uint64
+	ModifiedHardLimit   uint64
+	Stolen              Resources
+	Neighbors           int
+	Load                uint64
+	// LoadSource is "set" or "measured".
+	LoadSource string
+	Alive      bool
+	Ready      bool
+	Dying      bool
+	Draining   bool
+}
+
+// View returns what a watcher sees of the service now.
+// Every value is derived from the same snapshot of the state.
+func (service *SimulatedService) View() StateView {
+	var state = service.Snapshot()
+	var load, explicit = service.load.Load(time.Now())
+	var source = "measured"
+	if explicit {
+		source = "set"
+	}
+	var dying, draining = service.IsDying(), service.IsDraining()
+	return StateView{
+		MaxThroughput:       state.MaxThroughput,
+		SoftLimit:           state.SoftLimit,
+		HardLimit:           state.HardLimit,
+		AvailableThroughput: state.AvailableThroughput(),
+		ModifiedSoftLimit:   state.ModifiedSoftLimit(),
+		ModifiedHardLimit:   state.ModifiedHardLimit(),
+		Stolen:              state.Stolen,
+		Neighbors:           len(service.Neighbors()),
+		Load:                load,
+		LoadSource:          source,
+		Alive:               !dying && !state.Overloaded(load),
+		Ready:               !dying && !draining && state.ModifiedSoftLimit() >= load,
+		Dying:               dying,
+		Draining:            draining,
+	}
+}
+
+// Watch sends the view of the service straight away, and again whenever it
+// changes, until done is closed, the streams are ended, or send fails.
+func (service *SimulatedService) Watch(done <-chan struct{}, send func(StateView) error) error {
+	var ticker = time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	var last StateView
+	var sent bool
+	for {
+		// Take the channel before the view, so a change in between isn't missed.
+		var changed = service.changes.Changed()
+		var view = service.View()
+		if !sent || view != last {
+			if err := send(view); err != nil {
+				return err
+			}
+			last, sent = view, true
+		}
+		select {
+		case <-done:
+			return nil
+		case <-service.streamsEnded:
+			return nil
+		case <-changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+// SetLoad fixes the load held by the service, overriding the measured load.
+func (service *SimulatedService) SetLoad(load uint64) {
+	service.load.Set(load)
+	service.changes.Notify()
+	service.events.Record(api.Event{Type: api.EventLoadSet, Message: fmt.Sprintf("Load set to %d", load), Load: &load})
+}
+
+// ClearLoad goes back to measuring the load from the requests the service receives.
+func (service *SimulatedService) ClearLoad() {
+	service.load.Clear()
+	service.changes.Notify()
+	service.events.Record(api.Event{Type: api.EventLoadCleared, Message: "Load cleared, measuring it from requests"})
+}
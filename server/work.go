@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"runtime"
+	"sync/atomic"
+	"time"
+
+	"github.com/RobbieMcKinstry/hashicorp-presentation/api"
+)
+
+// The modes the server runs in. A simulated service models its throughput;
+// a real one burns CPU for every request to /v1/work, and measures it.
+const (
+	simulatedMode = "simulated"
+	realMode      = "real"
+)
+
+const (
+	// defaultWorkQueue and defaultWorkCPU are the defaults of the work-queue and work-cpu settings.
+	defaultWorkQueue = 100
+	defaultWorkCPU   = 10 * time.Millisecond
+	// calibrationRounds is how many rounds of work are timed at once while calibrating.
+	calibrationRounds = 1 << 16
+	// calibrationTime is how long each calibration trial lasts.
+	calibrationTime = 50 * time.Millisecond
+	// calibrationTrials is how many trials are run. The fastest one wins,
+	// since it was the least disturbed by anything else on the machine.
+	calibrationTrials = 3
+)
+
+// errWorkQueueFull is returned when every worker is busy and the queue is full.
+var errWorkQueueFull = errors.New("every worker is busy and the work queue is full")
+
+// A WorkPool runs the requests to /v1/work on a fixed number of workers,
+// each request burning a fixed amount of CPU. The work is calibrated once,
+// when the pool is built, so a request takes its CPU cost on an idle machine,
+// and longer when a real noisy neighbor competes for the CPU.
+type WorkPool struct {
+	// Workers is the number of requests worked on at once.
+	Workers int
+	// QueueLength is the number of requests which may wait for a worker.
+	QueueLength int
+	// Cost is the CPU time a request takes on an idle machine.
+	Cost time.Duration
+
+	// rounds is the number of rounds of work which take Cost.
+	rounds int
+	jobs   chan *workJob
+	// queued and busy count the jobs waiting for a worker, and being worked on.
+	queued, busy int32
+	// completed counts the jobs finished over a rolling one-second window.
+	completed *LoadTracker
+}
+
+// A workJob is a request waiting for, or being worked on by, a worker.
+type workJob struct {
+	ctx      context.Context
+	queuedAt time.Time
+	started  time.Time
+	finished time.Time
+	// sum is the result of the work, kept so the work can't be optimized away.
+	sum  uint64
+	done chan struct{}
+}
+
+// NewWorkPool is the constructor for a WorkPool. Zero workers starts one per CPU.
+func NewWorkPool(workers, queueLength uint64, cost time.Duration) *WorkPool {
+	if workers == 0 {
+		workers = uint64(runtime.NumCPU())
+	}
+	var pool = &WorkPool{
+		Workers:     int(workers),
+		QueueLength: int(queueLength),
+		Cost:        cost,
+		rounds:      int(float64(calibrate()) * float64(cost) / float64(time.Millisecond)),
+		jobs:        make(chan *workJob, queueLength),
+		completed:   NewLoadTracker(),
+	}
+	for i := 0; i < pool.Workers; i++ {
+		go pool.work()
+	}
+	return pool
+}
+
+// calibrate returns the number of rounds of work done in a millisecond.
+func calibrate() int {
+	var best int
+	for trial := 0; trial < calibrationTrials; trial++ {
+		var rounds int
+		var start = time.Now()
+		for time.Since(start) < calibrationTime {
+			burn(calibrationRounds)
+			rounds += calibrationRounds
+		}
+		var perMillisecond = int(float64(rounds) * float64(time.Millisecond) / float64(time.Since(start)))
+		if perMillisecond > best {
+			best = perMillisecond
+		}
+	}
+	return best
+}
+
+// burn does the given number of rounds of CPU-bound work, stepping a xorshift generator.
+// It's never inlined, so calibrate can't have the work optimized away.
+//
+//go:noinline
+func burn(rounds int) uint64 {
+	var x uint64 = 88172645463325252
+	for i := 0; i < rounds; i++ {
+		x ^= x << 13
+		x ^= x >> 7
+		x ^= x << 17
+	}
+	return x
+}
+
+// work runs jobs from the queue. It never returns.
+func (pool *WorkPool) work() {
+	for job := range pool.jobs {
+		atomic.AddInt32(&pool.queued, -1)
+		// Nobody's waiting for a job whose request was abandoned.
+		if job.ctx.Err() == nil {
+			atomic.AddInt32(&pool.busy, 1)
+			job.started = time.Now()
+			job.sum = burn(pool.rounds)
+			job.finished = time.Now()
+			atomic.AddInt32(&pool.busy, -1)
+			pool.completed.Observe(job.finished)
+		}
+		close(job.done)
+	}
+}
+
+// Do queues one request's worth of work, and waits for it to be done.
+// It returns errWorkQueueFull straight away if the queue is full,
+// or the context's error if the context is done first.
+func (pool *WorkPool) Do(ctx context.Context) (api.WorkResult, error) {
+	var job = &workJob{ctx: ctx, queuedAt: time.Now(), done: make(chan struct{})}
+	atomic.AddInt32(&pool.queued, 1)
+	select {
+	case pool.jobs <- job:
+	default:
+		atomic.AddInt32(&pool.queued, -1)
+		return api.WorkResult{}, errWorkQueueFull
+	}
+	select {
+	case <-job.done:
+	case <-ctx.Done():
+		return api.WorkResult{}, ctx.Err()
+	}
+	if job.finished.IsZero() {
+		return api.WorkResult{}, ctx.Err()
+	}
+	return api.WorkResult{
+		CPU:    toMillis(pool.Cost),
+		Queued: toMillis(job.started.Sub(job.queuedAt)),
+		Worked: toMillis(job.finished.Sub(job.started)),
+	}, nil
+}
+
+// Throughput returns the number of requests completed over the last second.
+func (pool *WorkPool) Throughput(now time.Time) uint64 {
+	var completed, _ = pool.completed.Load(now)
+	return completed
+}
+
+// explain describes the throughput of the pool, as measured at the given time.
+func (pool *WorkPool) explain(now time.Time) Explanation {
+	var throughput = pool.Throughput(now)
+	return Explanation{
+		Model:      realMode,
+		Regime:     "measured",
+		Throughput: throughput,
+		Values: map[string]float64{
+			"workers":      float64(pool.Workers),
+			"queue_length": float64(pool.QueueLength),
+			"queued":       float64(atomic.LoadInt32(&pool.queued)),
+			"busy":         float64(atomic.LoadInt32(&pool.busy)),
+			"cpu_ms":       toMillis(pool.Cost),
+			// The most requests per second the workers could complete on an idle machine.
+			"idle_capacity": float64(pool.Workers) * float64(time.Second) / float64(pool.Cost),
+		},
+	}
+}
+
+// SetWork puts the service in real mode, where the pool runs the requests
+// to /v1/work and the throughput is measured from them. A nil pool puts
+// the service back in simulated mode.
+func (service *SimulatedService) SetWork(pool *WorkPool) {
+	service.updateState(func(state *State) error {
+		state.Work = pool
+		return nil
+	})
+}
+
+// handleV1Work burns one request's worth of CPU on the work pool.
+func (service *SimulatedService) handleV1Work(w http.ResponseWriter, req *http.Request) {
+	var pool = service.Snapshot().Work
+	if pool == nil {
+		writeV1Error(w, &api.Error{Status: http.StatusNotFound, Code: api.CodeNotFound, Message: "Work is only done in real mode."})
+		return
+	}
+	var result, err = pool.Do(req.Context())
+	if err != nil {
+		writeV1Error(w, &api.Error{Status: http.StatusServiceUnavailable, Code: api.CodeUnavailable, Message: "The work wasn't done: " + err.Error() + "."})
+		return
+	}
+	writeV1(w, http.StatusOK, result)
+}